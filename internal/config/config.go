@@ -0,0 +1,116 @@
+// config 패키지는 서비스가 시작할 때 한 번 읽는 공통 설정을 담는다.
+//
+// 값은 명령행 플래그, 환경 변수, 서비스가 넘긴 기본값 순으로 우선한다. 잘못된 값은 오류로 돌려주어 서비스가 시작하지 않게 한다.
+// 기능별 세부 설정(재시도, 서킷 브레이커 등)은 각 기능 코드가 env.go의 도우미로 직접 읽는다.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 서비스 공통 설정
+type Config struct {
+	ServiceName      string        // OTEL_SERVICE_NAME: trace와 메트릭에 기록할 서비스 이름
+	Port             int           // PORT: HTTP 서버 포트
+	TempoEndpoint    string        // TEMPO_ENDPOINT: OTLP span 전송 대상 host:port (비어 있으면 프로토콜별 기본값)
+	SampleRatio      float64       // OTEL_TRACES_SAMPLER_ARG(없으면 SAMPLE_RATIO): 새 trace를 샘플링할 비율 0.0~1.0
+	ReceiverEndpoint string        // RECEIVER_ENDPOINT: receiver 주소 (sender 전용)
+	DummyInterval    time.Duration // DUMMY_REQUEST_INTERVAL: 더미 요청 간격 (sender 전용)
+}
+
+// 환경 변수와 명령행 플래그(args, 보통 os.Args[1:])에서 설정을 읽고 검증한다
+// 둘 다 없는 항목은 defaults의 값을 쓴다
+// 해석할 수 없거나 범위를 벗어난 값이 있으면 모든 문제를 모아 오류로 반환한다
+// DummyInterval은 defaults에 값이 있는 서비스(sender)에서만 0보다 커야 하고 -interval 플래그도 그때만 받는다
+// 플래그 뒤에 남은 인자(하위 명령 등)는 rest로 돌려준다
+func Load(defaults Config, args []string) (cfg Config, rest []string, err error) {
+	cfg = defaults
+	var errs []error
+
+	if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" {
+		cfg.ServiceName = v
+	}
+
+	if v := os.Getenv("PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PORT 값 %q는 정수가 아닙니다", v))
+		} else {
+			cfg.Port = port
+		}
+	}
+
+	if v := os.Getenv("TEMPO_ENDPOINT"); v != "" {
+		cfg.TempoEndpoint = v
+	}
+
+	// 표준 이름인 OTEL_TRACES_SAMPLER_ARG가 SAMPLE_RATIO보다 우선
+	for _, name := range []string{"OTEL_TRACES_SAMPLER_ARG", "SAMPLE_RATIO"} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s 값 %q는 숫자가 아닙니다", name, v))
+		} else {
+			cfg.SampleRatio = r
+		}
+		break
+	}
+
+	if v := os.Getenv("RECEIVER_ENDPOINT"); v != "" {
+		cfg.ReceiverEndpoint = v
+	}
+
+	if v := os.Getenv("DUMMY_REQUEST_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("DUMMY_REQUEST_INTERVAL 값 %q는 duration이 아닙니다 (예: 5s)", v))
+		} else {
+			cfg.DummyInterval = d
+		}
+	}
+
+	usesInterval := defaults.DummyInterval > 0
+	rest, err = cfg.parseFlags(args, usesInterval)
+	if err != nil {
+		return Config{}, nil, err
+	}
+
+	errs = append(errs, cfg.validate(usesInterval)...)
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, nil, fmt.Errorf("잘못된 설정: %w", err)
+	}
+	return cfg, rest, nil
+}
+
+// 값의 범위를 검사해 문제를 모두 반환한다
+// usesInterval이면 더미 요청 간격이 0보다 커야 한다
+func (c Config) validate(usesInterval bool) []error {
+	var errs []error
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("서비스 이름(OTEL_SERVICE_NAME)이 비어 있습니다"))
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("포트 %d가 범위(1~65535)를 벗어났습니다", c.Port))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("샘플링 비율 %v가 범위(0.0~1.0)를 벗어났습니다", c.SampleRatio))
+	}
+	if c.ReceiverEndpoint != "" {
+		u, err := url.Parse(c.ReceiverEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("receiver 주소 %q가 올바르지 않습니다 (예: http://receiver:8081)", c.ReceiverEndpoint))
+		}
+	}
+	if c.DummyInterval < 0 || (usesInterval && c.DummyInterval == 0) {
+		errs = append(errs, fmt.Errorf("더미 요청 간격 %v는 0보다 커야 합니다", c.DummyInterval))
+	}
+	return errs
+}
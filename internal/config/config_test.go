@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sender와 같은 모양의 기본값
+var testDefaults = Config{
+	ServiceName:      "monitoring-test-sender",
+	Port:             8080,
+	SampleRatio:      1,
+	ReceiverEndpoint: "http://localhost:8081",
+	DummyInterval:    5 * time.Second,
+}
+
+// Load가 읽는 환경 변수를 모두 비운다
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		"OTEL_SERVICE_NAME", "PORT", "TEMPO_ENDPOINT", "OTEL_TRACES_SAMPLER_ARG",
+		"SAMPLE_RATIO", "RECEIVER_ENDPOINT", "DUMMY_REQUEST_INTERVAL",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+	cfg, _, err := Load(testDefaults, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg != testDefaults {
+		t.Errorf("Load = %+v, want %+v", cfg, testDefaults)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("OTEL_SERVICE_NAME", "env-sender")
+	t.Setenv("PORT", "9000")
+	t.Setenv("TEMPO_ENDPOINT", "tempo.example:4317")
+	t.Setenv("SAMPLE_RATIO", "0.5")
+	t.Setenv("RECEIVER_ENDPOINT", "https://receiver.example")
+	t.Setenv("DUMMY_REQUEST_INTERVAL", "500ms")
+
+	cfg, _, err := Load(testDefaults, nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := Config{
+		ServiceName:      "env-sender",
+		Port:             9000,
+		TempoEndpoint:    "tempo.example:4317",
+		SampleRatio:      0.5,
+		ReceiverEndpoint: "https://receiver.example",
+		DummyInterval:    500 * time.Millisecond,
+	}
+	if cfg != want {
+		t.Errorf("Load = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadSampleRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		arg   string // OTEL_TRACES_SAMPLER_ARG
+		ratio string // SAMPLE_RATIO
+		want  float64
+	}{
+		{"설정 없음", "", "", 1},
+		{"OTEL_TRACES_SAMPLER_ARG", "0.25", "", 0.25},
+		{"SAMPLE_RATIO", "", "0.5", 0.5},
+		{"OTEL_TRACES_SAMPLER_ARG 우선", "0.1", "0.5", 0.1},
+		{"0", "0", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+			t.Setenv("SAMPLE_RATIO", tt.ratio)
+			cfg, _, err := Load(testDefaults, nil)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.SampleRatio != tt.want {
+				t.Errorf("SampleRatio = %v, want %v", cfg.SampleRatio, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		defaults Config
+		wantErr  string // 오류 메시지에 들어 있어야 하는 문자열
+	}{
+		{"PORT 정수 아님", map[string]string{"PORT": "http"}, testDefaults, "PORT"},
+		{"PORT 범위 초과", map[string]string{"PORT": "70000"}, testDefaults, "포트 70000"},
+		{"PORT 0", map[string]string{"PORT": "0"}, testDefaults, "포트 0"},
+		{"샘플링 비율 숫자 아님", map[string]string{"OTEL_TRACES_SAMPLER_ARG": "half"}, testDefaults, "OTEL_TRACES_SAMPLER_ARG"},
+		{"샘플링 비율 범위 초과", map[string]string{"SAMPLE_RATIO": "1.5"}, testDefaults, "샘플링 비율 1.5"},
+		{"샘플링 비율 음수", map[string]string{"SAMPLE_RATIO": "-0.1"}, testDefaults, "샘플링 비율 -0.1"},
+		{"receiver 주소 scheme 없음", map[string]string{"RECEIVER_ENDPOINT": "receiver:8081"}, testDefaults, "receiver 주소"},
+		{"receiver 주소 host 없음", map[string]string{"RECEIVER_ENDPOINT": "http://"}, testDefaults, "receiver 주소"},
+		{"간격 duration 아님", map[string]string{"DUMMY_REQUEST_INTERVAL": "5"}, testDefaults, "DUMMY_REQUEST_INTERVAL"},
+		{"간격 0", map[string]string{"DUMMY_REQUEST_INTERVAL": "0s"}, testDefaults, "간격"},
+		{"간격 음수", map[string]string{"DUMMY_REQUEST_INTERVAL": "-1s"}, testDefaults, "간격"},
+		{"서비스 이름 없음", nil, Config{Port: 8081, SampleRatio: 1}, "서비스 이름"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, _, err := Load(tt.defaults, nil)
+			if err == nil {
+				t.Fatal("오류가 없습니다")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("오류 %q에 %q가 없습니다", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "-1")
+	t.Setenv("SAMPLE_RATIO", "2")
+
+	_, _, err := Load(testDefaults, nil)
+	if err == nil || !strings.Contains(err.Error(), "포트") || !strings.Contains(err.Error(), "샘플링 비율") {
+		t.Errorf("Load 오류 = %v, want 포트와 샘플링 비율 문제를 함께 보고", err)
+	}
+}
+
+func TestLoadIntervalOnlyRequiredWhenDefaulted(t *testing.T) {
+	// receiver처럼 더미 요청 간격을 쓰지 않는 서비스는 0이어도 된다
+	clearEnv(t)
+	if _, _, err := Load(Config{ServiceName: "receiver", Port: 8081, SampleRatio: 1}, nil); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+}
+
+func TestLoadFlagsOverrideEnvOverrideDefaults(t *testing.T) {
+	// 네 항목 모두 환경 변수를 설정하고, 그중 두 개만 플래그로 덮어쓴다
+	clearEnv(t)
+	t.Setenv("OTEL_SERVICE_NAME", "env-sender")
+	t.Setenv("PORT", "9000")
+	t.Setenv("TEMPO_ENDPOINT", "env-tempo:4317")
+	t.Setenv("DUMMY_REQUEST_INTERVAL", "2s")
+
+	cfg, rest, err := Load(testDefaults, []string{"-port", "9100", "-interval", "1s", "loadtest", "-rps", "5"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// 플래그 뒤의 하위 명령과 그 인자는 그대로 남는다
+	if want := []string{"loadtest", "-rps", "5"}; !slices.Equal(rest, want) {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+	want := testDefaults
+	want.ServiceName = "env-sender"       // 환경 변수
+	want.Port = 9100                      // 플래그
+	want.TempoEndpoint = "env-tempo:4317" // 환경 변수
+	want.DummyInterval = time.Second      // 플래그
+	if cfg != want {
+		t.Errorf("Load = %+v, want %+v", cfg, want)
+	}
+
+	// 플래그는 환경 변수를 바꾸지 않는다
+	if got := os.Getenv("PORT"); got != "9000" {
+		t.Errorf("PORT 환경 변수 = %q, want 9000", got)
+	}
+
+	// 플래그도 환경 변수도 없으면 기본값
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	if got, _, _ := Load(testDefaults, nil); got.ServiceName != testDefaults.ServiceName {
+		t.Errorf("ServiceName = %q, want 기본값 %q", got.ServiceName, testDefaults.ServiceName)
+	}
+}
+
+func TestLoadFlagErrors(t *testing.T) {
+	receiverDefaults := Config{ServiceName: "receiver", Port: 8081, SampleRatio: 1}
+	tests := []struct {
+		name     string
+		defaults Config
+		args     []string
+	}{
+		{"알 수 없는 플래그", testDefaults, []string{"-unknown", "x"}},
+		{"정수가 아닌 포트", testDefaults, []string{"-port", "http"}},
+		{"범위를 벗어난 포트", testDefaults, []string{"-port", "70000"}},
+		{"0 간격", testDefaults, []string{"-interval", "0s"}},
+		{"receiver에는 -interval 없음", receiverDefaults, []string{"-interval", "1s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			if _, _, err := Load(tt.defaults, tt.args); err == nil {
+				t.Error("오류가 없습니다")
+			}
+		})
+	}
+}
+
+func TestLoadHelpFlag(t *testing.T) {
+	clearEnv(t)
+	if _, _, err := Load(testDefaults, []string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load(-h) 오류 = %v, want flag.ErrHelp", err)
+	}
+}
+
+func TestEnvHelpersFallBackToDefault(t *testing.T) {
+	tests := []struct {
+		value  string
+		pos    time.Duration // PositiveDuration 결과
+		nonNeg time.Duration // NonNegativeDuration 결과
+	}{
+		{"", time.Second, time.Second},
+		{"2s", 2 * time.Second, 2 * time.Second},
+		{"0s", time.Second, 0},
+		{"-1s", time.Second, time.Second},
+		{"soon", time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		t.Setenv("TEST_DURATION", tt.value)
+		if got := PositiveDuration("TEST_DURATION", time.Second); got != tt.pos {
+			t.Errorf("PositiveDuration(%q) = %v, want %v", tt.value, got, tt.pos)
+		}
+		if got := NonNegativeDuration("TEST_DURATION", time.Second); got != tt.nonNeg {
+			t.Errorf("NonNegativeDuration(%q) = %v, want %v", tt.value, got, tt.nonNeg)
+		}
+	}
+
+	t.Setenv("TEST_INT", "0")
+	if got := PositiveInt("TEST_INT", 3); got != 3 {
+		t.Errorf("PositiveInt(0) = %d, want 기본값 3", got)
+	}
+	if got := NonNegativeInt("TEST_INT", 3); got != 0 {
+		t.Errorf("NonNegativeInt(0) = %d, want 0", got)
+	}
+	t.Setenv("TEST_FLOAT", "-0.5")
+	if got := NonNegativeFloat("TEST_FLOAT", 1.5); got != 1.5 {
+		t.Errorf("NonNegativeFloat(-0.5) = %v, want 기본값 1.5", got)
+	}
+}
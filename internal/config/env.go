@@ -0,0 +1,53 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 기능별 세부 설정을 읽는 도우미
+// Load와 달리 잘못된 값으로 시작을 막지 않고, 경고를 남긴 뒤 기본값을 쓴다
+// (세부 기능 하나의 설정 실수로 데모 전체가 멈추지 않게 하기 위함)
+
+// 환경 변수 key를 parse로 해석하고, 해석할 수 없거나 valid를 만족하지 않으면 경고 후 def를 반환
+func envValue[T any](key string, def T, parse func(string) (T, error), valid func(T) bool) T {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	parsed, err := parse(v)
+	if err != nil || !valid(parsed) {
+		log.Printf("잘못된 %s 값 %q, 기본값(%v)을 사용합니다.", key, v, def)
+		return def
+	}
+	return parsed
+}
+
+func parseFloat(v string) (float64, error) { return strconv.ParseFloat(v, 64) }
+
+// PositiveInt는 양의 정수 환경 변수를 읽는다 (없거나 잘못된 값이면 경고를 남기고 기본값)
+func PositiveInt(key string, def int) int {
+	return envValue(key, def, strconv.Atoi, func(n int) bool { return n > 0 })
+}
+
+// NonNegativeInt는 0 이상의 정수 환경 변수를 읽는다 (0을 "사용하지 않음"으로 쓰는 설정용)
+func NonNegativeInt(key string, def int) int {
+	return envValue(key, def, strconv.Atoi, func(n int) bool { return n >= 0 })
+}
+
+// NonNegativeFloat는 0 이상의 실수 환경 변수를 읽는다
+func NonNegativeFloat(key string, def float64) float64 {
+	return envValue(key, def, parseFloat, func(f float64) bool { return f >= 0 })
+}
+
+// PositiveDuration은 양의 duration 환경 변수를 읽는다 (없거나 잘못된 값이면 경고를 남기고 기본값)
+func PositiveDuration(key string, def time.Duration) time.Duration {
+	return envValue(key, def, time.ParseDuration, func(d time.Duration) bool { return d > 0 })
+}
+
+// NonNegativeDuration은 0 이상의 duration 환경 변수를 읽는다 (0을 "제한 없음"으로 쓰는 설정용)
+func NonNegativeDuration(key string, def time.Duration) time.Duration {
+	return envValue(key, def, time.ParseDuration, func(d time.Duration) bool { return d >= 0 })
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 명령행 플래그로 cfg를 덮어쓴다
+//
+// 설정 우선순위: 플래그 > 환경 변수 > 코드의 기본값
+// Load가 환경 변수까지 반영한 cfg의 값을 각 플래그의 기본값으로 등록하므로,
+// 명령행에 지정된 플래그만 값을 바꾸고 나머지는 그대로 남는다.
+// -interval은 더미 요청을 보내는 서비스(usesInterval)에만 등록한다.
+// 플래그가 아닌 나머지 인자를 반환하고, -h/-help이면 flag.ErrHelp를 반환한다.
+func (c *Config) parseFlags(args []string, usesInterval bool) ([]string, error) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	fs.StringVar(&c.TempoEndpoint, "tempo-endpoint", c.TempoEndpoint, flagUsage("OTLP 전송 대상 host:port", "TEMPO_ENDPOINT"))
+	fs.StringVar(&c.ServiceName, "service-name", c.ServiceName, flagUsage("trace와 메트릭에 기록할 서비스 이름", "OTEL_SERVICE_NAME"))
+	fs.IntVar(&c.Port, "port", c.Port, flagUsage("HTTP 서버 포트", "PORT"))
+	if usesInterval {
+		fs.DurationVar(&c.DummyInterval, "interval", c.DummyInterval, flagUsage("더미 요청 간격 (예: 5s)", "DUMMY_REQUEST_INTERVAL"))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
+
+func flagUsage(usage, env string) string {
+	return fmt.Sprintf("%s (환경 변수 %s보다 우선)", usage, env)
+}
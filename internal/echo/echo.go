@@ -0,0 +1,59 @@
+// echo 패키지는 receiver의 gRPC Echo 서비스 정의를 담는다.
+//
+// protoc 없이 쓸 수 있도록 요청과 응답에 wrapperspb.StringValue를 사용하고
+// 서비스 설명을 직접 작성했다. 서버와 클라이언트가 같은 정의를 공유한다.
+package echo
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// gRPC 서비스와 메서드 이름
+const (
+	ServiceName = "playground.Echo"
+	echoMethod  = "/" + ServiceName + "/Echo"
+)
+
+// Echo RPC를 처리하는 함수
+type Handler func(ctx context.Context, message string) (string, error)
+
+// Echo 서비스를 gRPC 서버에 등록
+func RegisterServer(s *grpc.Server, h Handler) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: ServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Echo",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := new(wrapperspb.StringValue)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				call := func(ctx context.Context, req any) (any, error) {
+					out, err := h(ctx, req.(*wrapperspb.StringValue).GetValue())
+					if err != nil {
+						return nil, err
+					}
+					return wrapperspb.String(out), nil
+				}
+				if interceptor == nil {
+					return call(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{FullMethod: echoMethod}, call)
+			},
+		}},
+		Metadata: "echo",
+	}, nil)
+}
+
+// Echo RPC 호출
+func Call(ctx context.Context, cc grpc.ClientConnInterface, message string) (string, error) {
+	out := new(wrapperspb.StringValue)
+	if err := cc.Invoke(ctx, echoMethod, wrapperspb.String(message), out); err != nil {
+		return "", err
+	}
+	return out.GetValue(), nil
+}
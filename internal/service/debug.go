@@ -0,0 +1,37 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+
+	"observability-playground/internal/telemetry"
+)
+
+// DebugEndpointsEnabled는 호스트 이름 등 내부 정보를 드러내는 진단용 엔드포인트를 노출할지 반환한다
+// DEBUG_ENDPOINTS=true일 때만 노출
+func DebugEndpointsEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEBUG_ENDPOINTS"))
+	return enabled
+}
+
+// ResourceHandler는 span에 붙는 리소스 속성을 JSON으로 돌려주는 핸들러
+func ResourceHandler(w http.ResponseWriter, r *http.Request) {
+	LogWithTrace(r.Context(), "리소스 정보 요청", "method", r.Method, "path", r.URL.Path)
+
+	res := telemetry.Resource()
+	attrs := make(map[string]any)
+	if res != nil {
+		for iter := res.Iter(); iter.Next(); {
+			kv := iter.Attribute()
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"schema_url": res.SchemaURL(),
+		"attributes": attrs,
+	})
+}
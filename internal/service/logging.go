@@ -0,0 +1,143 @@
+// service 패키지는 sender와 receiver가 똑같이 쓰는 로깅, 서버 실행, 진단용 핸들러를 모은다.
+package service
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// info 이하 로그는 N개 중 1개만 남기고, warn 이상은 항상 남기는 slog.Handler 래퍼
+type samplingHandler struct {
+	slog.Handler
+	n       uint64
+	counter *atomic.Uint64
+}
+
+func newSamplingHandler(h slog.Handler, n uint64) *samplingHandler {
+	return &samplingHandler{Handler: h, n: n, counter: new(atomic.Uint64)}
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn && h.counter.Add(1)%h.n != 1 {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), n: h.n, counter: h.counter}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), n: h.n, counter: h.counter}
+}
+
+// 로그 레코드에 context의 trace_id와 span_id를 붙이는 slog.Handler 래퍼
+// Loki 등에서 로그와 trace를 서로 찾아갈 수 있게 한다
+type traceContextHandler struct {
+	slog.Handler
+}
+
+func (h traceContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h traceContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceContextHandler) WithGroup(name string) slog.Handler {
+	return traceContextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// 레코드를 여러 handler에 똑같이 넘기는 slog.Handler (stderr와 OTLP 동시 기록용)
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(teeHandler, len(t))
+	for i, h := range t {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	next := make(teeHandler, len(t))
+	for i, h := range t {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
+
+// LogWithTrace는 현재 span의 trace_id, span_id와 함께 info 로그를 남긴다
+// context에 요청 ID가 있으면 request_id도 붙여 같은 요청의 로그를 묶어 볼 수 있게 한다
+func LogWithTrace(ctx context.Context, msg string, args ...any) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		args = append(args, "request_id", id)
+	}
+	slog.InfoContext(ctx, msg, args...)
+}
+
+// InitLogger는 JSON 형식 slog 로거를 기본 로거로 설정 (log 패키지 출력도 slog를 거친다)
+// otlp가 true이면 같은 로그를 otelslog 브리지로 전역 LoggerProvider에도 넘긴다
+// 브리지는 context의 trace context를 레코드에 실으므로 수집기에서 로그와 trace가 연결된다
+// LOG_SAMPLE_N: info 이하 slog 로그를 N개 중 1개만 남김 (기본값 1 = 샘플링하지 않음, log 패키지 출력은 제외)
+func InitLogger(serviceName string, otlp bool) {
+	n := uint64(config.PositiveInt("LOG_SAMPLE_N", 1))
+
+	var handler slog.Handler = traceContextHandler{Handler: slog.NewJSONHandler(os.Stderr, nil)}
+	if otlp {
+		handler = teeHandler{handler, otelslog.NewHandler(serviceName)}
+	}
+	if n > 1 {
+		slog.SetDefault(slog.New(newSamplingHandler(handler, n)))
+		// log 패키지로 남긴 로그(log.Fatalf 포함)는 info 레벨로 들어오므로 샘플링하지 않는 handler로 따로 보낸다
+		log.SetOutput(slog.NewLogLogger(handler, slog.LevelInfo).Writer())
+		log.SetFlags(0)
+	} else {
+		slog.SetDefault(slog.New(handler))
+	}
+
+	if otlp {
+		slog.Info("로그를 OTLP로도 전송합니다.", "endpoint", os.Getenv("OTEL_LOGS_ENDPOINT"))
+	}
+	if n > 1 {
+		slog.Info("info 로그를 샘플링합니다 (warn 이상은 모두 기록)", "sample_n", n)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogWithTraceAddsTraceContext(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(traceContextHandler{Handler: slog.NewJSONHandler(&buf, nil)}))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = context.WithValue(ctx, requestIDKey{}, "req-1")
+
+	LogWithTrace(ctx, "테스트 로그", "path", "/")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("JSON 로그 파싱 실패: %v (%s)", err, buf.String())
+	}
+	want := map[string]string{
+		"msg":        "테스트 로그",
+		"trace_id":   traceID.String(),
+		"span_id":    spanID.String(),
+		"request_id": "req-1",
+		"path":       "/",
+	}
+	for key, v := range want {
+		if entry[key] != v {
+			t.Errorf("%s = %v, want %s", key, entry[key], v)
+		}
+	}
+}
+
+func TestLogWithoutSpanHasNoTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(traceContextHandler{Handler: slog.NewJSONHandler(&buf, nil)})
+
+	logger.InfoContext(context.Background(), "span 없음")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("JSON 로그 파싱 실패: %v", err)
+	}
+	if _, ok := entry["trace_id"]; ok {
+		t.Errorf("span이 없는데 trace_id가 있습니다: %v", entry["trace_id"])
+	}
+}
+
+// 내보낸 로그 레코드를 모으는 exporter
+type recordingLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+func (e *recordingLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestInitLoggerExportsTraceContext(t *testing.T) {
+	t.Setenv("LOG_SAMPLE_N", "")
+	exporter := &recordingLogExporter{}
+	lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	prevProvider, prevLogger := global.GetLoggerProvider(), slog.Default()
+	global.SetLoggerProvider(lp)
+	t.Cleanup(func() {
+		global.SetLoggerProvider(prevProvider)
+		slog.SetDefault(prevLogger)
+		lp.Shutdown(context.Background())
+	})
+
+	InitLogger("test-service", true)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	LogWithTrace(ctx, "OTLP 로그")
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	for _, r := range exporter.records {
+		if r.Body().AsString() != "OTLP 로그" {
+			continue
+		}
+		if r.TraceID() != traceID || r.SpanID() != spanID {
+			t.Errorf("로그 레코드 trace_id/span_id = %s/%s, want %s/%s", r.TraceID(), r.SpanID(), traceID, spanID)
+		}
+		return
+	}
+	t.Fatalf("OTLP로 내보낸 로그 레코드가 없습니다 (%d개)", len(exporter.records))
+}
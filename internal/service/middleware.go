@@ -0,0 +1,224 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/telemetry"
+)
+
+// Middleware는 핸들러를 감싸는 HTTP 미들웨어
+type Middleware func(http.Handler) http.Handler
+
+// Chain은 h를 middlewares로 감싼다. 앞에 있는 미들웨어가 바깥쪽에서 먼저 실행된다
+// Chain(h, a, b)는 a(b(h))와 같다
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// WithRoute는 등록한 경로 패턴을 span의 http.route 속성으로 기록하는 미들웨어를 만든다
+// 실제 요청 경로가 아닌 등록한 패턴(예: 모든 경로를 받는 /)이므로 Grafana에서 카디널리티 걱정 없이 경로별로 묶을 수 있다
+func WithRoute(route string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRouteKey.String(route))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// StatusRecorder는 핸들러가 쓴 상태 코드를 기록하는 ResponseWriter
+type StatusRecorder struct {
+	http.ResponseWriter
+	status   int
+	onStatus func(status int)
+}
+
+// NewStatusRecorder는 w를 감싼 StatusRecorder를 만든다
+// onStatus가 nil이 아니면 상태 코드가 처음 정해질 때 한 번 호출한다
+func NewStatusRecorder(w http.ResponseWriter, onStatus func(status int)) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, onStatus: onStatus}
+}
+
+func (w *StatusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.setStatus(code)
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *StatusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.setStatus(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *StatusRecorder) setStatus(code int) {
+	w.status = code
+	if w.onStatus != nil {
+		w.onStatus(code)
+	}
+}
+
+// Status는 핸들러가 쓴 상태 코드 (아무것도 쓰지 않았으면 net/http가 보내는 200)
+func (w *StatusRecorder) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// WithStatusClass는 응답 상태 코드와 2xx/4xx/5xx 같은 등급을 span에 기록하는 미들웨어
+// TraceQL이나 대시보드에서 { span.http.status_class = "5xx" } 처럼 경로와 상관없이 묶어 볼 수 있다
+// 핸들러 span은 응답을 쓰기 전에 끝나지 않으므로, 상태 코드를 처음 쓰는 시점에 http.status_code를 함께 기록한다
+func WithStatusClass(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := telemetry.WithHandlerSpans(r.Context())
+		rec := NewStatusRecorder(w, func(status int) {
+			telemetry.SetHandlerSpanAttributes(ctx, semconv.HTTPStatusCodeKey.Int(status))
+		})
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		status := rec.Status()
+		trace.SpanFromContext(ctx).SetAttributes(
+			semconv.HTTPStatusCodeKey.Int(status),
+			attribute.String("http.status_class", fmt.Sprintf("%dxx", status/100)),
+		)
+	})
+}
+
+// HTTP 요청 수 카운터와 지연 시간 히스토그램 (InitRequestMetrics 전에는 nil이라 기록하지 않는다)
+var (
+	requestCounter  metric.Int64Counter
+	requestDuration metric.Float64Histogram
+)
+
+// InitRequestMetrics는 전역 MeterProvider의 serviceName 이름 meter에 WithMetrics가 쓰는 instrument를 만든다
+func InitRequestMetrics(serviceName string) {
+	meter := otel.Meter(serviceName)
+
+	var err error
+	requestCounter, err = meter.Int64Counter("http.server.requests",
+		metric.WithDescription("처리한 HTTP 요청 수"),
+	)
+	if err != nil {
+		log.Printf("요청 카운터 생성 실패: %v", err)
+	}
+	requestDuration, err = meter.Float64Histogram(telemetry.RequestDurationInstrument,
+		metric.WithDescription("HTTP 요청 처리 시간"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		log.Printf("지연 시간 히스토그램 생성 실패: %v", err)
+	}
+}
+
+// WithMetrics는 요청 수와 처리 시간을 경로와 상태 코드별로 기록하는 미들웨어를 만든다
+// 경로는 카디널리티를 낮추기 위해 실제 URL 대신 등록한 패턴(route)을 쓴다
+func WithMetrics(route string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := NewStatusRecorder(w, nil)
+			next.ServeHTTP(rec, r)
+
+			attrs := metric.WithAttributes(
+				attribute.String("http.route", route),
+				attribute.Int("http.status_code", rec.Status()),
+			)
+			if requestCounter != nil {
+				requestCounter.Add(r.Context(), 1, attrs)
+			}
+			if requestDuration != nil {
+				requestDuration.Record(r.Context(), float64(time.Since(start).Microseconds())/1000, attrs)
+			}
+		})
+	}
+}
+
+// WithRecovery는 핸들러에서 발생한 panic을 잡아 span에 오류로 기록하고 500을 반환하는 미들웨어
+// 프로세스는 계속 실행되며, 스택은 로그로 남긴다
+func WithRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// 연결을 끊기 위한 panic은 net/http가 처리하도록 그대로 전달
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := fmt.Errorf("panic: %v", v)
+			span := trace.SpanFromContext(r.Context())
+			span.RecordError(err, trace.WithStackTrace(true))
+			span.SetStatus(codes.Error, err.Error())
+			slog.Error("핸들러 panic 복구", "method", r.Method, "path", r.URL.Path, "error", err, "stack", string(debug.Stack()))
+
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "내부 서버 오류가 발생했습니다!\n")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithTraceIDHeader는 현재 요청의 trace ID를 X-Trace-Id 응답 헤더로 돌려주는 미들웨어
+// 클라이언트가 응답만 보고 Tempo에서 해당 trace를 찾을 수 있다
+func WithTraceIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+			w.Header().Set("X-Trace-Id", sc.TraceID().String())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithRequestAttributes는 요청의 User-Agent, 본문 크기, 클라이언트 IP를 서버 span에 기록하는 미들웨어
+// 클라이언트 IP는 X-Forwarded-For의 첫 번째 주소를 우선하고, 없으면 연결 주소를 쓴다
+func WithRequestAttributes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attrs := []attribute.KeyValue{
+			attribute.String("http.user_agent", r.UserAgent()),
+			attribute.String("http.client_ip", clientIP(r)),
+		}
+		if r.ContentLength >= 0 {
+			attrs = append(attrs, attribute.Int64("http.request_content_length", r.ContentLength))
+		}
+		trace.SpanFromContext(r.Context()).SetAttributes(attrs...)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 요청을 보낸 클라이언트의 IP
+func clientIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		first, _, _ := strings.Cut(xff, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
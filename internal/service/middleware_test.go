@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// h를 서버 span 안에서 실행하는 InstrumentedMux 서버를 띄우고, 끝난 span을 모으는 exporter를 반환
+func newTracedServer(t *testing.T, h http.Handler) (*httptest.Server, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+
+	mux := NewInstrumentedMux("test-service")
+	mux.HandleTraced("/", "test", h)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		srv.Close()
+		otel.SetTracerProvider(prev)
+		tp.Shutdown(context.Background())
+	})
+	return srv, exporter
+}
+
+// 서버로 GET 요청을 보내고 응답을 닫는다
+func get(t *testing.T, url string, header http.Header) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+// span의 속성 값 (없으면 nil)
+func attr(s tracetest.SpanStub, key string) any {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func TestWithStatusClass(t *testing.T) {
+	tests := []struct {
+		path       string
+		wantStatus int64
+		wantClass  string
+	}{
+		{"/ok", 200, "2xx"}, // 아무것도 쓰지 않으면 200
+		{"/missing", 404, "4xx"},
+		{"/fail", 503, "5xx"},
+	}
+	srv, exporter := newTracedServer(t, WithStatusClass(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/fail":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})))
+
+	for _, tt := range tests {
+		exporter.Reset()
+		get(t, srv.URL+tt.path, nil)
+		spans := exporter.GetSpans()
+		if len(spans) != 1 {
+			t.Fatalf("%s: span 수 = %d, want 1", tt.path, len(spans))
+		}
+		if got := attr(spans[0], "http.status_code"); got != tt.wantStatus {
+			t.Errorf("%s: http.status_code = %v, want %d", tt.path, got, tt.wantStatus)
+		}
+		if got := attr(spans[0], "http.status_class"); got != tt.wantClass {
+			t.Errorf("%s: http.status_class = %v, want %s", tt.path, got, tt.wantClass)
+		}
+	}
+}
+
+func TestWithTraceIDHeader(t *testing.T) {
+	srv, exporter := newTracedServer(t, WithTraceIDHeader(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	resp := get(t, srv.URL, nil)
+	got := resp.Header.Get("X-Trace-Id")
+	if !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(got) {
+		t.Fatalf("X-Trace-Id = %q, want 16진수 32자", got)
+	}
+	if want := exporter.GetSpans()[0].SpanContext.TraceID().String(); got != want {
+		t.Errorf("X-Trace-Id = %s, want 서버 span의 trace ID %s", got, want)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	var fromContext string
+	srv, exporter := newTracedServer(t, WithRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromContext = RequestIDFromContext(r.Context())
+	})))
+
+	// 헤더가 없으면 UUID를 만들어 응답, context, span에 같은 값을 쓴다
+	resp := get(t, srv.URL, nil)
+	got := resp.Header.Get(RequestIDHeader)
+	if _, err := uuid.Parse(got); err != nil {
+		t.Fatalf("%s = %q, want UUID (%v)", RequestIDHeader, got, err)
+	}
+	if fromContext != got {
+		t.Errorf("context의 요청 ID = %q, want %q", fromContext, got)
+	}
+	if v := attr(exporter.GetSpans()[0], "request.id"); v != got {
+		t.Errorf("span request.id = %v, want %q", v, got)
+	}
+
+	// 들어온 ID는 그대로 돌려준다
+	resp = get(t, srv.URL, http.Header{RequestIDHeader: {"req-123"}})
+	if got := resp.Header.Get(RequestIDHeader); got != "req-123" || fromContext != "req-123" {
+		t.Errorf("%s = %q (context %q), want req-123", RequestIDHeader, got, fromContext)
+	}
+}
+
+func TestWithRequestAttributes(t *testing.T) {
+	srv, exporter := newTracedServer(t, WithRequestAttributes(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		wantIP string
+	}{
+		{"연결 주소", http.Header{"User-Agent": {"playground-test/1.0"}}, "127.0.0.1"},
+		{"X-Forwarded-For 첫 주소", http.Header{"User-Agent": {"playground-test/1.0"}, "X-Forwarded-For": {"203.0.113.7, 10.0.0.1"}}, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			get(t, srv.URL, tt.header)
+			span := exporter.GetSpans()[0]
+			if got := attr(span, "http.user_agent"); got != "playground-test/1.0" {
+				t.Errorf("http.user_agent = %v, want playground-test/1.0", got)
+			}
+			if got := attr(span, "http.client_ip"); got != tt.wantIP {
+				t.Errorf("http.client_ip = %v, want %s", got, tt.wantIP)
+			}
+			if got := attr(span, "http.request_content_length"); got != int64(0) {
+				t.Errorf("http.request_content_length = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestChainAppliesMiddlewaresOutsideIn(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), mark("a"), mark("b"), mark("c"))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if want := []string{"a", "b", "c", "handler"}; !slices.Equal(order, want) {
+		t.Errorf("실행 순서 = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// InstrumentedMux는 HandleTraced로 등록한 핸들러마다 otelhttp 서버 span을 만드는 ServeMux
+// /metrics처럼 trace로 남기지 않을 경로는 ServeMux의 Handle로 그대로 등록한다
+type InstrumentedMux struct {
+	*http.ServeMux
+	serviceName string
+}
+
+// NewInstrumentedMux는 serviceName을 서버 이름으로 기록하는 InstrumentedMux를 만든다
+func NewInstrumentedMux(serviceName string) *InstrumentedMux {
+	return &InstrumentedMux{ServeMux: http.NewServeMux(), serviceName: serviceName}
+}
+
+// HandleTraced는 h를 otelhttp.NewHandler로 감싸 pattern에 등록한다 (서버 span 이름은 operation)
+func (m *InstrumentedMux) HandleTraced(pattern, operation string, h http.Handler) {
+	m.Handle(pattern, otelhttp.NewHandler(h, operation, otelhttp.WithServerName(m.serviceName)))
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHandleTracedCreatesServerSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		tp.Shutdown(context.Background())
+	})
+
+	mux := NewInstrumentedMux("test-service")
+	var handlerSpan trace.SpanContext
+	mux.HandleTraced("/hello", "hello", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	mux.Handle("/plain", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	for _, path := range []string{"/hello", "/plain"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("span 수 = %d, want 1 (/plain은 span을 만들지 않아야 한다)", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "hello" || span.SpanKind != trace.SpanKindServer {
+		t.Errorf("span = %q (%v), want hello (server)", span.Name, span.SpanKind)
+	}
+	if span.SpanContext.SpanID() != handlerSpan.SpanID() {
+		t.Errorf("핸들러 context의 span %s가 서버 span %s와 다릅니다", handlerSpan.SpanID(), span.SpanContext.SpanID())
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// SDK 내부 오류(export 실패 등)를 로그와 메트릭으로 남기는 ErrorHandler
+type sdkErrorHandler struct {
+	errors metric.Int64Counter
+}
+
+func (h *sdkErrorHandler) Handle(err error) {
+	errType := fmt.Sprintf("%T", err)
+	slog.Error("OpenTelemetry SDK 오류", "error.type", errType, "error", err)
+
+	if h.errors != nil {
+		h.errors.Add(context.Background(), 1, metric.WithAttributes(attribute.String("error.type", errType)))
+	}
+}
+
+// InitErrorHandler는 전역 ErrorHandler를 등록한다 (기본값은 stderr에 그대로 출력)
+// 오류 카운터는 serviceName 이름의 meter에 만든다
+func InitErrorHandler(serviceName string) {
+	counter, err := otel.Meter(serviceName).Int64Counter("otel.sdk.errors",
+		metric.WithDescription("OpenTelemetry SDK 내부 오류 수"),
+	)
+	if err != nil {
+		log.Printf("SDK 오류 카운터 생성 실패: %v", err)
+	}
+	otel.SetErrorHandler(&sdkErrorHandler{errors: counter})
+}
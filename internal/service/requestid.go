@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader는 요청 ID를 주고받는 헤더
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext는 context에 저장된 요청 ID를 반환한다 (없으면 빈 문자열)
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID는 요청 ID를 context와 span(request.id)에 기록하고 응답 헤더로 돌려주는 미들웨어
+// 들어온 X-Request-Id가 없으면 UUID를 새로 만든다
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", id))
+		w.Header().Set(RequestIDHeader, id)
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
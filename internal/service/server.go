@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// 종료 요청과 그 출처를 전달하는 채널
+var shutdownRequests = make(chan string, 1)
+
+// RequestShutdown은 서버 종료를 요청한다. 이미 종료가 진행 중이면 무시
+func RequestShutdown(source string) {
+	select {
+	case shutdownRequests <- source:
+	default:
+	}
+}
+
+// RunServer는 서버를 실행하고, 종료 요청이나 SIGINT/SIGTERM을 받으면 진행 중인 요청을 마무리한 뒤 반환
+// 반환 후 main의 defer에서 tracer provider가 종료되므로 마지막 요청의 span까지 전송된다
+func RunServer(srv *http.Server) error {
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var source string
+		select {
+		case source = <-shutdownRequests:
+		case <-sigCtx.Done():
+			source = "signal"
+		}
+		log.Printf("종료 요청 수신 (출처: %s), 진행 중인 요청을 마무리합니다...", source)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Error("서버 종료 실패", "error", err)
+		}
+	}()
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-done
+	log.Println("서버가 종료되었습니다.")
+	return nil
+}
+
+// AdminShutdownHandler는 서버를 정상 종료시키는 관리용 핸들러 (POST만 허용)
+// ADMIN_TOKEN이 설정되어 있으면 X-Admin-Token 헤더가 일치해야 한다
+func AdminShutdownHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if token := os.Getenv("ADMIN_TOKEN"); token != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) != 1 {
+		slog.WarnContext(r.Context(), "잘못된 토큰으로 종료 요청", "remote", r.RemoteAddr)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	source := fmt.Sprintf("POST /admin/shutdown (remote=%s, user-agent=%q)", r.RemoteAddr, r.UserAgent())
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "종료를 시작합니다.\n")
+
+	RequestShutdown(source)
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"observability-playground/internal/telemetry"
+)
+
+// VersionHandler는 빌드 정보를 JSON으로 돌려주는 핸들러
+func VersionHandler(w http.ResponseWriter, r *http.Request) {
+	LogWithTrace(r.Context(), "버전 정보 요청", "method", r.Method, "path", r.URL.Path)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(telemetry.ReadBuildInfo())
+}
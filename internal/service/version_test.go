@@ -0,0 +1,31 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"observability-playground/internal/telemetry"
+)
+
+func TestVersionHandlerReturnsLdflagsValues(t *testing.T) {
+	// go build -ldflags "-X ..."로 넣는 값과 같다
+	prev := []string{telemetry.Version, telemetry.Commit, telemetry.BuildTime}
+	telemetry.Version, telemetry.Commit, telemetry.BuildTime = "v1.2.3", "0123456789abcdef", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() { telemetry.Version, telemetry.Commit, telemetry.BuildTime = prev[0], prev[1], prev[2] })
+
+	rec := httptest.NewRecorder()
+	VersionHandler(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var got telemetry.BuildInfo
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("응답 JSON 해석 실패: %v", err)
+	}
+	if got.Version != "v1.2.3" || got.Revision != "0123456789abcdef" || got.BuildTime != "2026-01-02T03:04:05Z" {
+		t.Errorf("/version = %+v, want ldflags로 넣은 값", got)
+	}
+}
@@ -0,0 +1,138 @@
+// stats 패키지는 Prometheus 없이도 볼 수 있는 간단한 요청 통계를 모은다.
+//
+// 경로별 요청 수와 오류 수는 atomic 카운터로, 지연 시간은 최근 요청의 링 버퍼로
+// 관리하며 Handler가 이를 JSON으로 돌려준다.
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"observability-playground/internal/service"
+)
+
+// 지연 시간 백분위 계산에 쓰는 최근 요청 수
+const latencySamples = 1024
+
+// 경로 하나의 카운터
+type routeStats struct {
+	requests atomic.Int64
+	errors   atomic.Int64 // 5xx 응답 수
+}
+
+var (
+	routesMu sync.RWMutex
+	routes   = map[string]*routeStats{}
+
+	latencyMu   sync.Mutex
+	latencies   [latencySamples]float64 // ms, 링 버퍼
+	latencyNext int
+	latencyFull bool
+)
+
+func routeFor(route string) *routeStats {
+	routesMu.RLock()
+	s, ok := routes[route]
+	routesMu.RUnlock()
+	if ok {
+		return s
+	}
+
+	routesMu.Lock()
+	defer routesMu.Unlock()
+	if s, ok := routes[route]; ok {
+		return s
+	}
+	s = &routeStats{}
+	routes[route] = s
+	return s
+}
+
+func recordLatency(ms float64) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	latencies[latencyNext] = ms
+	latencyNext = (latencyNext + 1) % latencySamples
+	if latencyNext == 0 {
+		latencyFull = true
+	}
+}
+
+// 요청 수, 5xx 오류 수, 처리 시간을 route 기준으로 집계하는 미들웨어를 만든다
+func Middleware(route string) service.Middleware {
+	s := routeFor(route)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := service.NewStatusRecorder(w, nil)
+			next.ServeHTTP(rec, r)
+
+			s.requests.Add(1)
+			if rec.Status() >= http.StatusInternalServerError {
+				s.errors.Add(1)
+			}
+			recordLatency(float64(time.Since(start).Microseconds()) / 1000)
+		})
+	}
+}
+
+// 정렬된 값에서 p 백분위 값 (nearest-rank)
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+// 현재 통계를 JSON으로 돌려주는 /stats 핸들러
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type routeJSON struct {
+			Requests int64 `json:"requests"`
+			Errors   int64 `json:"errors"`
+		}
+
+		perRoute := map[string]routeJSON{}
+		var totalRequests, totalErrors int64
+		routesMu.RLock()
+		for route, s := range routes {
+			rs := routeJSON{Requests: s.requests.Load(), Errors: s.errors.Load()}
+			perRoute[route] = rs
+			totalRequests += rs.Requests
+			totalErrors += rs.Errors
+		}
+		routesMu.RUnlock()
+
+		latencyMu.Lock()
+		n := latencyNext
+		if latencyFull {
+			n = latencySamples
+		}
+		sorted := append([]float64(nil), latencies[:n]...)
+		latencyMu.Unlock()
+		sort.Float64s(sorted)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"routes":         perRoute,
+			"total_requests": totalRequests,
+			"total_errors":   totalErrors,
+			"latency_ms": map[string]any{
+				"samples": len(sorted),
+				"p50":     percentile(sorted, 0.50),
+				"p95":     percentile(sorted, 0.95),
+			},
+		})
+	})
+}
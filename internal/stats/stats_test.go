@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 패키지 전역 통계를 비운다 (같은 프로세스에서 테스트를 반복 실행할 때)
+func reset() {
+	routesMu.Lock()
+	routes = map[string]*routeStats{}
+	routesMu.Unlock()
+
+	latencyMu.Lock()
+	latencyNext, latencyFull = 0, false
+	latencyMu.Unlock()
+}
+
+func TestHandlerReportsCountsAndLatency(t *testing.T) {
+	reset()
+	mux := http.NewServeMux()
+	mux.Handle("/ok", Middleware("/ok")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	mux.Handle("/fail", Middleware("/fail")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})))
+	mux.Handle("/sleep", Middleware("/sleep")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	})))
+	mux.Handle("/stats", Handler())
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for path, n := range map[string]int{"/ok": 5, "/fail": 2, "/sleep": 3} {
+		for i := 0; i < n; i++ {
+			resp, err := srv.Client().Get(srv.URL + path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+		}
+	}
+
+	resp, err := srv.Client().Get(srv.URL + "/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got struct {
+		Routes map[string]struct {
+			Requests int64 `json:"requests"`
+			Errors   int64 `json:"errors"`
+		} `json:"routes"`
+		TotalRequests int64 `json:"total_requests"`
+		TotalErrors   int64 `json:"total_errors"`
+		LatencyMS     struct {
+			Samples int     `json:"samples"`
+			P50     float64 `json:"p50"`
+			P95     float64 `json:"p95"`
+		} `json:"latency_ms"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("JSON 파싱 실패: %v", err)
+	}
+
+	want := map[string][2]int64{"/ok": {5, 0}, "/fail": {2, 2}, "/sleep": {3, 0}}
+	for route, w := range want {
+		r := got.Routes[route]
+		if r.Requests != w[0] || r.Errors != w[1] {
+			t.Errorf("%s: requests=%d errors=%d, want %d, %d", route, r.Requests, r.Errors, w[0], w[1])
+		}
+	}
+	if got.TotalRequests != 10 || got.TotalErrors != 2 {
+		t.Errorf("total_requests=%d total_errors=%d, want 10, 2", got.TotalRequests, got.TotalErrors)
+	}
+	// /stats 요청 자체는 미들웨어를 거치지 않으므로 샘플은 10개
+	// 10개 중 3개가 20ms 이상이므로 p50은 그보다 작고 p95는 20ms 이상이어야 한다
+	if got.LatencyMS.Samples != 10 {
+		t.Errorf("latency_ms.samples = %d, want 10", got.LatencyMS.Samples)
+	}
+	if got.LatencyMS.P95 < 20 || got.LatencyMS.P50 >= 20 || got.LatencyMS.P50 > got.LatencyMS.P95 {
+		t.Errorf("p50=%vms p95=%vms, want p50 < 20 <= p95", got.LatencyMS.P50, got.LatencyMS.P95)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0.50, 5},
+		{0.95, 10},
+		{0, 1},
+		{1, 10},
+	}
+	for _, tt := range tests {
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("빈 목록의 percentile = %v, want 0", got)
+	}
+}
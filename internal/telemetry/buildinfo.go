@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+// 빌드할 때 -ldflags -X로 넣는 값 (비어 있으면 debug.ReadBuildInfo() 값을 쓴다)
+// 예: go build -ldflags "-X observability-playground/internal/telemetry.Version=v1.2.0
+//
+//	-X observability-playground/internal/telemetry.Commit=$(git rev-parse HEAD)
+//	-X observability-playground/internal/telemetry.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Docker 빌드에는 .git이 없어 VCS 정보가 기록되지 않으므로 Dockerfile이 빌드 인자로 넘긴다
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// 바이너리의 빌드 정보
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"vcs_revision"`
+	Time      string `json:"vcs_time,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"vcs_modified"`
+	GoVersion string `json:"go_version"`
+}
+
+// debug.ReadBuildInfo()에서 버전과 VCS 정보를 추출하고, ldflags로 넣은 값이 있으면 그 값을 우선한다
+// 빌드 정보가 없으면 "unknown"으로 채운다
+func ReadBuildInfo() BuildInfo {
+	info := readGoBuildInfo()
+	if Commit != "" {
+		info.Revision = Commit
+	}
+	if Version != "" {
+		info.Version = Version
+	} else if Commit != "" && info.Version == "unknown" {
+		info.Version = shortRevision(Commit)
+	}
+	info.BuildTime = BuildTime
+	return info
+}
+
+// 12자리로 줄인 커밋 해시
+func shortRevision(rev string) string {
+	if len(rev) > 12 {
+		return rev[:12]
+	}
+	return rev
+}
+
+// Go 툴체인이 바이너리에 기록한 빌드 정보
+func readGoBuildInfo() BuildInfo {
+	info := BuildInfo{Version: "unknown", Revision: "unknown"}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Revision = s.Value
+		case "vcs.time":
+			info.Time = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+
+	// 모듈 버전이 없으면 커밋 해시로 대신한다
+	switch {
+	case bi.Main.Version != "" && bi.Main.Version != "(devel)":
+		info.Version = bi.Main.Version
+	case info.Revision != "unknown":
+		info.Version = shortRevision(info.Revision)
+	}
+
+	return info
+}
+
+// 리소스에 붙일 버전 속성
+func (b BuildInfo) Attributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.ServiceVersionKey.String(b.Version),
+		attribute.String("vcs.revision", b.Revision),
+	}
+}
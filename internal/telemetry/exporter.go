@@ -0,0 +1,297 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"observability-playground/internal/config"
+)
+
+// OTEL_TRACES_EXPORTER(쉼표로 구분한 목록, 예: "otlp,stdout")에 지정한 exporter를 모두 생성
+// 값이 없으면 otlp 하나만 사용하고, 같은 이름이 여러 번 나오면 한 번만 만든다
+func newSpanExporters(ctx context.Context, tempoEndpoint string) ([]sdktrace.SpanExporter, error) {
+	v := os.Getenv("OTEL_TRACES_EXPORTER")
+	if strings.TrimSpace(v) == "" {
+		v = "otlp"
+	}
+
+	var exporters []sdktrace.SpanExporter
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		exporter, err := newSpanExporter(ctx, name, tempoEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+	}
+	if len(exporters) == 0 {
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER에 exporter가 없습니다: %q", v)
+	}
+	return exporters, nil
+}
+
+// exporter 이름에 따라 span exporter 생성
+// otlp (기본값): tempoEndpoint(TEMPO_ENDPOINT)로 OTLP 전송 (gRPC 또는 HTTP)
+// zipkin: ZIPKIN_ENDPOINT로 Zipkin 형식 전송
+// stdout: 표준 출력으로 span을 보기 좋게 출력 (Tempo 없이 로컬에서 확인할 때)
+// none: span을 내보내지 않음
+func newSpanExporter(ctx context.Context, name, tempoEndpoint string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "otlp":
+		return newOTLPExporter(ctx, tempoEndpoint)
+	case "zipkin":
+		return newZipkinExporter()
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("stdout exporter 생성 실패: %w", err)
+		}
+		return exporter, nil
+	case "none":
+		log.Println("OTEL_TRACES_EXPORTER=none, span을 내보내지 않습니다.")
+		return noopExporter{}, nil
+	default:
+		return nil, fmt.Errorf("지원하지 않는 OTEL_TRACES_EXPORTER 값: %q", name)
+	}
+}
+
+// 받은 span을 모두 버리는 exporter
+type noopExporter struct{}
+
+func (noopExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return nil }
+func (noopExporter) Shutdown(context.Context) error                             { return nil }
+
+// Tempo로 전송하는 OTLP exporter 생성 (tempoEndpoint가 비어 있으면 프로토콜별 기본 주소)
+// OTEL_EXPORTER_OTLP_PROTOCOL: grpc (기본값, 포트 4317) 또는 http/protobuf (포트 4318)
+func newOTLPExporter(ctx context.Context, tempoEndpoint string) (sdktrace.SpanExporter, error) {
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	switch protocol {
+	case "", "grpc":
+		return newOTLPGRPCExporter(ctx, tempoEndpoint)
+	case "http/protobuf":
+		return newOTLPHTTPExporter(ctx, tempoEndpoint)
+	default:
+		return nil, fmt.Errorf("지원하지 않는 OTEL_EXPORTER_OTLP_PROTOCOL 값: %q", protocol)
+	}
+}
+
+// OTLP gRPC exporter 생성
+func newOTLPGRPCExporter(ctx context.Context, tempoEndpoint string) (sdktrace.SpanExporter, error) {
+	if tempoEndpoint == "" {
+		tempoEndpoint = "tempo:4317" // 기본값
+	}
+
+	creds, err := getOTLPCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(tempoEndpoint),
+		otlptracegrpc.WithTLSCredentials(creds),
+		otlptracegrpc.WithTimeout(getOTLPTimeout()),
+	}
+	client := otlptracegrpc.NewClient(append(opts, otlpRetryOptions()...)...)
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("OTLP exporter 생성 실패: %w", err)
+	}
+	return exporter, nil
+}
+
+// OTLP/HTTP exporter 생성 (gRPC를 받지 않는 게이트웨이 앞단용)
+func newOTLPHTTPExporter(ctx context.Context, tempoEndpoint string) (sdktrace.SpanExporter, error) {
+	if tempoEndpoint == "" {
+		tempoEndpoint = "tempo:4318" // 기본값
+	}
+
+	tlsConfig, err := getOTLPTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(tempoEndpoint),
+		otlptracehttp.WithTimeout(getOTLPTimeout()),
+	}
+	if tlsConfig == nil {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	} else {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	}
+
+	exporter, err := otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+	if err != nil {
+		return nil, fmt.Errorf("OTLP/HTTP exporter 생성 실패: %w", err)
+	}
+	return exporter, nil
+}
+
+// OTLP 연결에 사용할 TLS 설정 (nil이면 TLS 없이 연결)
+// OTEL_EXPORTER_OTLP_INSECURE: true(기본값)이면 TLS 없이 연결 (테스트 환경용)
+// OTEL_EXPORTER_OTLP_CERTIFICATE: TLS 사용 시 신뢰할 CA 인증서(PEM) 경로 (없으면 시스템 인증서 사용)
+func getOTLPTLSConfig() (*tls.Config, error) {
+	insecureMode := true
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_INSECURE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Printf("잘못된 OTEL_EXPORTER_OTLP_INSECURE 값 %q, 기본값 true를 사용합니다.", v)
+		} else {
+			insecureMode = b
+		}
+	}
+	if insecureMode {
+		return nil, nil
+	}
+
+	certPath := os.Getenv("OTEL_EXPORTER_OTLP_CERTIFICATE")
+	if certPath == "" {
+		// RootCAs가 nil이면 시스템 인증서 풀을 사용
+		return &tls.Config{}, nil
+	}
+
+	pem, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_CERTIFICATE 읽기 실패: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("OTEL_EXPORTER_OTLP_CERTIFICATE %q에서 인증서를 찾을 수 없습니다", certPath)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
+// OTLP gRPC 연결에 사용할 전송 보안 설정
+func getOTLPCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig, err := getOTLPTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		return insecure.NewCredentials(), nil
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// OTEL_EXPORTER_OTLP_TIMEOUT 파싱 (기본값: SDK 기본값인 10s)
+// 표준 명세의 밀리초 정수("30000")와 Go duration 문자열("30s")을 모두 허용
+func getOTLPTimeout() time.Duration {
+	if ms, err := strconv.Atoi(os.Getenv("OTEL_EXPORTER_OTLP_TIMEOUT")); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return config.PositiveDuration("OTEL_EXPORTER_OTLP_TIMEOUT", 10*time.Second)
+}
+
+// Zipkin 백엔드로 전송하는 exporter 생성
+// 서비스 이름 등 리소스 속성은 exporter가 Zipkin의 localEndpoint로 변환한다
+func newZipkinExporter() (sdktrace.SpanExporter, error) {
+	endpoint := os.Getenv("ZIPKIN_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "http://zipkin:9411/api/v2/spans" // 기본값
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("잘못된 ZIPKIN_ENDPOINT 값: %q", endpoint)
+	}
+
+	exporter, err := zipkin.New(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Zipkin exporter 생성 실패: %w", err)
+	}
+	log.Printf("Zipkin exporter를 사용합니다 (%s)", endpoint)
+	return exporter, nil
+}
+
+// 예상 크기가 임계값을 넘는 배치를 여러 번에 나눠 export하는 SpanExporter 래퍼
+// 수집기의 최대 메시지 크기를 넘겨 배치 전체가 실패하는 것을 막는다
+type splittingExporter struct {
+	sdktrace.SpanExporter
+	maxBytes int
+}
+
+// EXPORT_MAX_PAYLOAD_BYTES가 설정되면 exporter를 분할 래퍼로 감싼다 (기본값 0 = 분할하지 않음)
+func newSplittingExporter(exporter sdktrace.SpanExporter) sdktrace.SpanExporter {
+	n := config.NonNegativeInt("EXPORT_MAX_PAYLOAD_BYTES", 0)
+	if n == 0 {
+		return exporter
+	}
+	log.Printf("export 배치를 약 %d바이트 단위로 분할합니다.", n)
+	return &splittingExporter{SpanExporter: exporter, maxBytes: n}
+}
+
+func (e *splittingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var chunks [][]sdktrace.ReadOnlySpan
+	start, size := 0, 0
+	for i, s := range spans {
+		n := estimateSpanSize(s)
+		if i > start && size+n > e.maxBytes {
+			chunks = append(chunks, spans[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	chunks = append(chunks, spans[start:])
+
+	if len(chunks) == 1 {
+		return e.SpanExporter.ExportSpans(ctx, spans)
+	}
+
+	log.Printf("큰 export 배치를 분할합니다: span %d개 -> 배치 %d개", len(spans), len(chunks))
+
+	// 한 조각이 실패해도 나머지는 계속 전송
+	var errs []error
+	for _, chunk := range chunks {
+		if err := e.SpanExporter.ExportSpans(ctx, chunk); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// span 하나가 전송될 때의 대략적인 크기 (바이트)
+func estimateSpanSize(s sdktrace.ReadOnlySpan) int {
+	// trace/span ID, 시각, 종류, 상태 등 고정 필드
+	size := 64 + len(s.Name()) + len(s.Status().Description)
+	size += estimateAttributesSize(s.Attributes())
+	for _, e := range s.Events() {
+		size += 16 + len(e.Name) + estimateAttributesSize(e.Attributes)
+	}
+	for _, l := range s.Links() {
+		size += 32 + estimateAttributesSize(l.Attributes)
+	}
+	return size
+}
+
+func estimateAttributesSize(attrs []attribute.KeyValue) int {
+	size := 0
+	for _, kv := range attrs {
+		size += len(kv.Key) + len(kv.Value.Emit())
+	}
+	return size
+}
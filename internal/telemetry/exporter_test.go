@@ -0,0 +1,210 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGetOTLPCredentials(t *testing.T) {
+	t.Run("기본값은 insecure", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+		creds, err := getOTLPCredentials()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := creds.Info().SecurityProtocol; got != "insecure" {
+			t.Errorf("SecurityProtocol = %q, want insecure", got)
+		}
+	})
+
+	t.Run("INSECURE=false이면 시스템 인증서로 TLS", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "false")
+		t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", "")
+		creds, err := getOTLPCredentials()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := creds.Info().SecurityProtocol; got != "tls" {
+			t.Errorf("SecurityProtocol = %q, want tls", got)
+		}
+		tlsConfig, _ := getOTLPTLSConfig()
+		if tlsConfig.RootCAs != nil {
+			t.Error("CERTIFICATE가 없으면 RootCAs는 nil(시스템 인증서)이어야 합니다")
+		}
+	})
+
+	t.Run("CERTIFICATE로 CA 지정", func(t *testing.T) {
+		// httptest의 TLS 서버 인증서를 CA 파일로 쓴다
+		srv := httptest.NewTLSServer(nil)
+		defer srv.Close()
+		certPath := filepath.Join(t.TempDir(), "ca.pem")
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+		if err := os.WriteFile(certPath, pemBytes, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "false")
+		t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", certPath)
+		tlsConfig, err := getOTLPTLSConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := x509.NewCertPool()
+		want.AddCert(srv.Certificate())
+		if tlsConfig.RootCAs == nil || !tlsConfig.RootCAs.Equal(want) {
+			t.Error("RootCAs에 지정한 CA가 없습니다")
+		}
+	})
+
+	t.Run("잘못된 CERTIFICATE", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.pem")
+		if err := os.WriteFile(empty, []byte("not a cert"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "false")
+		for _, path := range []string{filepath.Join(t.TempDir(), "missing.pem"), empty} {
+			t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", path)
+			if _, err := getOTLPCredentials(); err == nil {
+				t.Errorf("CERTIFICATE=%s: 오류가 없습니다", path)
+			}
+		}
+	})
+}
+
+func TestNewSpanExporterTypes(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "")
+	t.Setenv("ZIPKIN_ENDPOINT", "")
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		check func(sdktrace.SpanExporter) bool
+	}{
+		{"otlp", func(e sdktrace.SpanExporter) bool { _, ok := e.(*otlptrace.Exporter); return ok }},
+		{"zipkin", func(e sdktrace.SpanExporter) bool { _, ok := e.(*zipkin.Exporter); return ok }},
+		{"stdout", func(e sdktrace.SpanExporter) bool { _, ok := e.(*stdouttrace.Exporter); return ok }},
+		{"none", func(e sdktrace.SpanExporter) bool { _, ok := e.(noopExporter); return ok }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := newSpanExporter(ctx, tt.name, "")
+			if err != nil {
+				t.Fatalf("newSpanExporter(%q): %v", tt.name, err)
+			}
+			defer exporter.Shutdown(ctx)
+			if !tt.check(exporter) {
+				t.Errorf("newSpanExporter(%q) = %T", tt.name, exporter)
+			}
+		})
+	}
+
+	if _, err := newSpanExporter(ctx, "jaeger", ""); err == nil {
+		t.Error("지원하지 않는 exporter인데 오류가 없습니다")
+	}
+}
+
+func TestNewOTLPExporterProtocols(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	ctx := context.Background()
+
+	for _, protocol := range []string{"", "grpc", "http/protobuf"} {
+		t.Run("protocol="+protocol, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", protocol)
+			// 연결은 span을 보낼 때 맺으므로 주소에 서버가 없어도 생성은 성공한다
+			exporter, err := newOTLPExporter(ctx, "")
+			if err != nil {
+				t.Fatalf("newOTLPExporter: %v", err)
+			}
+			if exporter == nil {
+				t.Fatal("exporter가 nil입니다")
+			}
+			exporter.Shutdown(ctx)
+		})
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	if _, err := newOTLPExporter(ctx, ""); err == nil {
+		t.Error("지원하지 않는 프로토콜인데 오류가 없습니다")
+	}
+}
+
+func TestNewSpanExportersSendOTLPHeaders(t *testing.T) {
+	// 인증 헤더가 필요한 수집기 대신 받은 헤더를 기록하는 서버
+	headers := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case headers <- r.Header.Clone():
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	// 값에 "="가 들어간 쌍과 URL 인코딩된 값
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-scope-orgid=tenant-1, authorization=Basic%20dXNlcjpwYXNz, x-token=a=b==")
+
+	ctx := context.Background()
+	exporters, err := newSpanExporters(ctx, strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("newSpanExporters: %v", err)
+	}
+	if len(exporters) != 1 {
+		t.Fatalf("exporter 수 = %d, want 1", len(exporters))
+	}
+	defer exporters[0].Shutdown(ctx)
+
+	stub := tracetest.SpanStub{Name: "op"}
+	if err := exporters[0].ExportSpans(ctx, []sdktrace.ReadOnlySpan{stub.Snapshot()}); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	got := <-headers
+	want := map[string]string{
+		"X-Scope-Orgid": "tenant-1",
+		"Authorization": "Basic dXNlcjpwYXNz",
+		"X-Token":       "a=b==",
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("헤더 %s = %q, want %q", k, got.Get(k), v)
+		}
+	}
+}
+
+func TestNewZipkinExporterEndpoint(t *testing.T) {
+	ctx := context.Background()
+	for _, endpoint := range []string{"", "http://zipkin.example:9411/api/v2/spans", "https://zipkin.example/api/v2/spans"} {
+		t.Run("valid="+endpoint, func(t *testing.T) {
+			t.Setenv("ZIPKIN_ENDPOINT", endpoint)
+			exporter, err := newZipkinExporter()
+			if err != nil {
+				t.Fatalf("newZipkinExporter: %v", err)
+			}
+			exporter.Shutdown(ctx)
+		})
+	}
+	for _, endpoint := range []string{"zipkin:9411", "ftp://zipkin.example/spans", "http://", "://bad"} {
+		t.Run("invalid="+endpoint, func(t *testing.T) {
+			t.Setenv("ZIPKIN_ENDPOINT", endpoint)
+			if _, err := newZipkinExporter(); err == nil {
+				t.Errorf("ZIPKIN_ENDPOINT=%q: 오류가 없습니다", endpoint)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 특정 경로의 서버 span과 그 하위 span을 내보내지 않는 SpanProcessor 래퍼
+// Kubernetes가 계속 호출하는 /health 같은 요청으로 Tempo가 가득 차는 것을 막는다 (TRACE_IGNORE_ROUTES로 직접 켠다)
+//
+// 걸러내기 규칙:
+//   - 서버 span의 경로(http.target 또는 url.path, 쿼리 문자열 제외)가 목록에 있으면 무시한다
+//   - 무시한 span을 부모로 하는 같은 프로세스의 하위 span도 무시한다
+//   - 무시한 span은 감싼 processor(batcher)에 전달하지 않는다
+type routeFilterProcessor struct {
+	sdktrace.SpanProcessor
+	routes  map[string]bool
+	ignored sync.Map // trace.SpanID -> struct{}
+}
+
+// TRACE_IGNORE_ROUTES(쉼표 구분, 예: "/health") 파싱
+// 설정하지 않으면 아무것도 걸러내지 않는다
+func getIgnoredRoutes() map[string]bool {
+	v := os.Getenv("TRACE_IGNORE_ROUTES")
+
+	routes := make(map[string]bool)
+	for _, route := range strings.Split(v, ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			routes[route] = true
+		}
+	}
+	if len(routes) > 0 {
+		log.Printf("trace에서 제외할 경로: %s", v)
+	}
+	return routes
+}
+
+// routes에 있는 경로를 걸러내도록 processor를 감싼다 (routes가 비어 있으면 그대로 반환)
+func newRouteFilterProcessor(next sdktrace.SpanProcessor, routes map[string]bool) sdktrace.SpanProcessor {
+	if len(routes) == 0 {
+		return next
+	}
+	return &routeFilterProcessor{SpanProcessor: next, routes: routes}
+}
+
+func (p *routeFilterProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	if p.shouldIgnore(s) {
+		p.ignored.Store(s.SpanContext().SpanID(), struct{}{})
+		return
+	}
+	p.SpanProcessor.OnStart(ctx, s)
+}
+
+func (p *routeFilterProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if _, ok := p.ignored.LoadAndDelete(s.SpanContext().SpanID()); ok {
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+func (p *routeFilterProcessor) shouldIgnore(s sdktrace.ReadWriteSpan) bool {
+	if parent := s.Parent(); parent.IsValid() && !parent.IsRemote() {
+		if _, ok := p.ignored.Load(parent.SpanID()); ok {
+			return true
+		}
+	}
+	if s.SpanKind() != trace.SpanKindServer {
+		return false
+	}
+	for _, kv := range s.Attributes() {
+		if kv.Key == attribute.Key("http.target") || kv.Key == attribute.Key("url.path") {
+			// http.target에는 쿼리가 붙어 있을 수 있다 (/health?probe=liveness)
+			path, _, _ := strings.Cut(kv.Value.AsString(), "?")
+			return p.routes[path]
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package telemetry
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRouteFilterDropsIgnoredRoutes(t *testing.T) {
+	t.Setenv("TRACE_IGNORE_ROUTES", "/health")
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+		newRouteFilterProcessor(sdktrace.NewSimpleSpanProcessor(exporter), getIgnoredRoutes()),
+	))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	tracer := tp.Tracer("test")
+
+	for _, target := range []string{"/health", "/health?probe=liveness", "/slow", "/slow?delay=1s"} {
+		ctx, server := tracer.Start(context.Background(), target, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.target", target)))
+		_, child := tracer.Start(ctx, "child "+target)
+		child.End()
+		server.End()
+	}
+
+	var got []string
+	for _, s := range exporter.GetSpans() {
+		got = append(got, s.Name)
+	}
+	want := []string{"child /slow", "/slow", "child /slow?delay=1s", "/slow?delay=1s"}
+	if !slices.Equal(got, want) {
+		t.Errorf("내보낸 span = %q, want %q", got, want)
+	}
+}
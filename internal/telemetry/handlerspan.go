@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 서버 span 바로 아래에서 시작한 핸들러 span 목록
+// 미들웨어는 핸들러가 만든 span을 직접 볼 수 없으므로, span processor가 시작 시점에 여기 모아 둔다
+type handlerSpans struct {
+	parent trace.SpanID
+	mu     sync.Mutex
+	spans  []sdktrace.ReadWriteSpan
+}
+
+type handlerSpansKey struct{}
+
+// WithHandlerSpans는 ctx의 현재 span(서버 span)의 자식으로 시작하는 span을 모으도록 표시한 context를 반환한다
+// SetHandlerSpanAttributes로 모은 span에 속성을 붙일 수 있다
+func WithHandlerSpans(ctx context.Context) context.Context {
+	hs := &handlerSpans{parent: trace.SpanContextFromContext(ctx).SpanID()}
+	return context.WithValue(ctx, handlerSpansKey{}, hs)
+}
+
+// SetHandlerSpanAttributes는 WithHandlerSpans로 표시한 context에서 시작해 아직 끝나지 않은 핸들러 span에 속성을 붙인다
+func SetHandlerSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	hs, ok := ctx.Value(handlerSpansKey{}).(*handlerSpans)
+	if !ok {
+		return
+	}
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	for _, s := range hs.spans {
+		s.SetAttributes(attrs...) // 이미 끝난 span에는 적용되지 않는다
+	}
+}
+
+// 부모 context에 handlerSpans가 있고 부모가 서버 span인 span을 handlerSpans에 등록하는 processor
+type handlerSpanProcessor struct{}
+
+func (handlerSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	hs, ok := parent.Value(handlerSpansKey{}).(*handlerSpans)
+	if !ok || s.Parent().SpanID() != hs.parent {
+		return
+	}
+	hs.mu.Lock()
+	hs.spans = append(hs.spans, s)
+	hs.mu.Unlock()
+}
+
+func (handlerSpanProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (handlerSpanProcessor) Shutdown(context.Context) error   { return nil }
+func (handlerSpanProcessor) ForceFlush(context.Context) error { return nil }
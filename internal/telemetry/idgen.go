@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 데모 전용: 모든 루트 span에 같은 trace ID를 부여하는 IDGenerator
+// 스크린샷이나 튜토리얼에서 고정된 trace ID를 가리키기 위한 것으로,
+// trace ID의 고유성이 깨지므로 실제 환경에서는 절대 사용하지 말 것
+type fixedTraceIDGenerator struct {
+	traceID trace.TraceID
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// FIXED_TRACE_ID 값(32자리 16진수)으로 생성기를 만든다
+// 값이 없거나 잘못되었으면 nil을 반환한다
+func newFixedTraceIDGenerator(hex string) *fixedTraceIDGenerator {
+	if hex == "" {
+		return nil
+	}
+	traceID, err := trace.TraceIDFromHex(hex)
+	if err != nil {
+		log.Printf("잘못된 FIXED_TRACE_ID 값 %q, 무시합니다: %v", hex, err)
+		return nil
+	}
+
+	log.Printf("경고: 고정 trace ID %s를 사용합니다 (데모 전용, trace ID 고유성이 보장되지 않음)", traceID)
+	return &fixedTraceIDGenerator{
+		traceID: traceID,
+		rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (g *fixedTraceIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	return g.traceID, g.NewSpanID(ctx, g.traceID)
+}
+
+func (g *fixedTraceIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sid := trace.SpanID{}
+	for {
+		g.rand.Read(sid[:])
+		if sid.IsValid() {
+			return sid
+		}
+	}
+}
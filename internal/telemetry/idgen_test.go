@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 1부터 차례로 증가하는 trace ID와 span ID를 부여하는 테스트용 IDGenerator
+type countingIDGenerator struct {
+	mu        sync.Mutex
+	traceNext uint64
+	spanNext  uint64
+}
+
+func (g *countingIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	g.traceNext++
+	tid := trace.TraceID{}
+	binary.BigEndian.PutUint64(tid[8:], g.traceNext)
+	g.mu.Unlock()
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+func (g *countingIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.spanNext++
+	sid := trace.SpanID{}
+	binary.BigEndian.PutUint64(sid[:], g.spanNext)
+	return sid
+}
+
+// 끝난 span을 메모리 exporter에 바로 넘기는 TracerProvider를 InitTracer로 만든다
+// 외부로는 아무것도 보내지 않으며 테스트가 끝나면 provider를 종료한다
+func newTestTracerProvider(t *testing.T, options ...Option) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	t.Setenv("TRACE_RECORDER_SIZE", "0")
+
+	exporter := tracetest.NewInMemoryExporter()
+	options = append(options, WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)))
+	tp, err := InitTracer(context.Background(), config.Config{ServiceName: "test", SampleRatio: 1}, options...)
+	if err != nil {
+		t.Fatalf("InitTracer: %v", err)
+	}
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return tp, exporter
+}
+
+func TestWithIDGeneratorGivesPredictableIDs(t *testing.T) {
+	tp, exporter := newTestTracerProvider(t, WithIDGenerator(&countingIDGenerator{}))
+	tracer := tp.Tracer("test")
+
+	ctx, first := tracer.Start(context.Background(), "first")
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	first.End()
+	_, second := tracer.Start(context.Background(), "second")
+	second.End()
+
+	want := map[string]string{
+		"first":  "00000000000000000000000000000001",
+		"child":  "00000000000000000000000000000001",
+		"second": "00000000000000000000000000000002",
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != len(want) {
+		t.Fatalf("span 수 = %d, want %d", len(spans), len(want))
+	}
+	for _, s := range spans {
+		if got := s.SpanContext.TraceID().String(); got != want[s.Name] {
+			t.Errorf("%s의 trace ID = %s, want %s", s.Name, got, want[s.Name])
+		}
+	}
+	if got := spans[0].Parent.SpanID().String(); got != "0000000000000001" {
+		t.Errorf("child의 부모 span ID = %s, want 0000000000000001", got)
+	}
+}
@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// LoggerProvider를 만들어 전역으로 등록한다
+// OTEL_LOGS_ENDPOINT가 설정된 경우에만 그 주소로 OTLP gRPC 전송하며 (Tempo는 로그를 받지 않는다),
+// 설정되지 않았으면 nil을 반환한다
+func InitLogger(ctx context.Context, serviceName string) (*sdklog.LoggerProvider, error) {
+	endpoint := os.Getenv("OTEL_LOGS_ENDPOINT")
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	creds, err := getOTLPCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlploggrpc.New(ctx,
+		otlploggrpc.WithEndpoint(endpoint),
+		otlploggrpc.WithTLSCredentials(creds),
+		otlploggrpc.WithTimeout(getOTLPTimeout()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("OTLP log exporter 생성 실패: %w", err)
+	}
+
+	res, err := newResource(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+	global.SetLoggerProvider(lp)
+	return lp, nil
+}
@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"observability-playground/internal/config"
+)
+
+// 서비스들이 HTTP 요청 처리 시간을 기록하는 히스토그램 이름 (단위: ms)
+const RequestDurationInstrument = "http.server.request.duration_ms"
+
+// OTLP로 메트릭을 내보내는 주기
+// OTEL_METRIC_EXPORT_INTERVAL: 표준과 같이 ms 단위 정수 (기본값 10000, SDK 기본값 60초는 데모에서 보기에 너무 길다)
+func getMetricExportInterval() time.Duration {
+	return time.Duration(config.PositiveInt("OTEL_METRIC_EXPORT_INTERVAL", 10000)) * time.Millisecond
+}
+
+// OTEL_METRIC_EXPORT_INTERVAL 주기로 exporter에 메트릭을 보내는 reader
+func newPeriodicReader(exporter sdkmetric.Exporter) *sdkmetric.PeriodicReader {
+	return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(getMetricExportInterval()))
+}
+
+// MeterProvider를 만들어 전역으로 등록한다
+// 메트릭은 항상 Prometheus가 수집할 수 있도록 MetricsHandler로 노출하고,
+// OTEL_METRICS_ENDPOINT가 설정된 경우에만 그 주소로도 OTLP gRPC 전송한다 (Tempo는 메트릭을 받지 않는다)
+func InitMeter(ctx context.Context, serviceName string) (*sdkmetric.MeterProvider, error) {
+	var opts []sdkmetric.Option
+	endpoint := os.Getenv("OTEL_METRICS_ENDPOINT")
+	if endpoint != "" {
+		creds, err := getOTLPCredentials()
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := otlpmetricgrpc.New(ctx,
+			otlpmetricgrpc.WithEndpoint(endpoint),
+			otlpmetricgrpc.WithTLSCredentials(creds),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("OTLP metric exporter 생성 실패: %w", err)
+		}
+		opts = append(opts, sdkmetric.WithReader(newPeriodicReader(exporter)))
+	}
+
+	// 기본 registry에 등록되므로 promhttp.Handler()로 그대로 노출된다
+	promExporter, err := prometheus.New()
+	if err != nil {
+		return nil, fmt.Errorf("Prometheus exporter 생성 실패: %w", err)
+	}
+
+	res, err := newResource(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(append(opts,
+		sdkmetric.WithReader(promExporter),
+		sdkmetric.WithResource(res),
+		sdkmetric.WithView(newLatencyView()),
+	)...)
+	otel.SetMeterProvider(mp)
+
+	// 고루틴 수, GC, 메모리 등 Go 런타임 메트릭 수집
+	if err := runtime.Start(runtime.WithMeterProvider(mp)); err != nil {
+		return nil, fmt.Errorf("런타임 메트릭 수집 시작 실패: %w", err)
+	}
+
+	if endpoint != "" {
+		log.Printf("메트릭을 %s로도 전송합니다.", endpoint)
+	}
+	return mp, nil
+}
+
+// Prometheus 수집용 /metrics 핸들러
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
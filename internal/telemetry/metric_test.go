@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Export 호출 수만 세는 metric exporter
+type countingMetricExporter struct{ exports atomic.Int32 }
+
+func (e *countingMetricExporter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(k)
+}
+func (e *countingMetricExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+func (e *countingMetricExporter) Export(context.Context, *metricdata.ResourceMetrics) error {
+	e.exports.Add(1)
+	return nil
+}
+func (e *countingMetricExporter) ForceFlush(context.Context) error { return nil }
+func (e *countingMetricExporter) Shutdown(context.Context) error   { return nil }
+
+func TestGetMetricExportInterval(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 10 * time.Second},
+		{"500", 500 * time.Millisecond},
+		{"0", 10 * time.Second},
+		{"5s", 10 * time.Second}, // ms 단위 정수만 받는다
+	}
+	for _, tt := range tests {
+		t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", tt.value)
+		if got := getMetricExportInterval(); got != tt.want {
+			t.Errorf("OTEL_METRIC_EXPORT_INTERVAL=%q: getMetricExportInterval() = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPeriodicReaderUsesExportInterval(t *testing.T) {
+	t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "20")
+	exporter := &countingMetricExporter{}
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(newPeriodicReader(exporter)))
+	counter, _ := mp.Meter("test").Int64Counter("test_total")
+	counter.Add(context.Background(), 1)
+
+	// 기본 주기(10초)였다면 이 시간 안에는 한 번도 내보내지 않는다
+	time.Sleep(200 * time.Millisecond)
+	got := exporter.exports.Load()
+	mp.Shutdown(context.Background())
+	if got < 2 {
+		t.Errorf("200ms 동안 export %d번, want 20ms 주기로 여러 번", got)
+	}
+}
+
+func TestInitMeterRegistersRuntimeMetrics(t *testing.T) {
+	// Prometheus exporter는 기본 registry에 등록되므로 이 패키지에서 한 번만 초기화한다
+	t.Setenv("OTEL_METRICS_ENDPOINT", "")
+	mp, err := InitMeter(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("InitMeter: %v", err)
+	}
+	t.Cleanup(func() { mp.Shutdown(context.Background()) })
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	// Prometheus 기본 Go collector의 go_* 메트릭과 구분하기 위해 OTel runtime 계측의 이름과 scope로 확인한다
+	for _, name := range []string{"process_runtime_go_goroutines", "process_runtime_go_mem_heap_alloc_bytes"} {
+		if !strings.Contains(body, name+`{otel_scope_name="go.opentelemetry.io/contrib/instrumentation/runtime"`) {
+			t.Errorf("/metrics에 런타임 메트릭 %s가 없습니다", name)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// OTEL_PROPAGATORS(쉼표 구분, 기본값 "tracecontext,baggage")로 composite propagator 생성
+// tracecontext: W3C traceparent/tracestate
+// baggage: W3C baggage
+// b3: Zipkin B3 단일 헤더 (b3multi: X-B3-* 다중 헤더) - B3 기반 시스템과 연동할 때
+// 알 수 없는 이름은 경고를 남기고 건너뛴다
+func newPropagator() propagation.TextMapPropagator {
+	v := os.Getenv("OTEL_PROPAGATORS")
+	if strings.TrimSpace(v) == "" {
+		v = "tracecontext,baggage"
+	}
+
+	var propagators []propagation.TextMapPropagator
+	for _, name := range strings.Split(v, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		default:
+			log.Printf("알 수 없는 OTEL_PROPAGATORS 항목 %q, 무시합니다.", name)
+		}
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
+
+// W3C baggage 명세가 보장하도록 요구하는 최소 한도
+const (
+	defaultBaggageMaxMembers = 64
+	defaultBaggageMaxBytes   = 8192
+)
+
+// baggage 항목 수와 크기를 제한하는 TextMapPropagator 래퍼
+// 전파 전후로 한도를 넘는 항목을 잘라낸다
+// Inject에서 잘라내면 현재(클라이언트) span에 baggage.trimmed=true를 바로 기록한다
+// Extract 시점의 span은 기록되지 않는 원격 span이므로, 잘라낸 항목 수를 context에 담아 두고 WithBaggageTrimmed가 서버 span에 기록한다
+type baggageLimitingPropagator struct {
+	propagation.TextMapPropagator
+	maxMembers int
+	maxBytes   int
+}
+
+// BAGGAGE_MAX_MEMBERS, BAGGAGE_MAX_BYTES 환경 변수로 한도를 읽어 propagator를 감싼다
+func newBaggageLimitingPropagator(p propagation.TextMapPropagator) *baggageLimitingPropagator {
+	return &baggageLimitingPropagator{
+		TextMapPropagator: p,
+		maxMembers:        config.PositiveInt("BAGGAGE_MAX_MEMBERS", defaultBaggageMaxMembers),
+		maxBytes:          config.PositiveInt("BAGGAGE_MAX_BYTES", defaultBaggageMaxBytes),
+	}
+}
+
+func (p *baggageLimitingPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	ctx, dropped := p.limit(ctx)
+	if dropped > 0 {
+		setBaggageTrimmed(trace.SpanFromContext(ctx), dropped)
+	}
+	p.TextMapPropagator.Inject(ctx, carrier)
+}
+
+func (p *baggageLimitingPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	ctx, dropped := p.limit(p.TextMapPropagator.Extract(ctx, carrier))
+	if dropped > 0 {
+		ctx = context.WithValue(ctx, baggageDroppedKey{}, dropped)
+	}
+	return ctx
+}
+
+// context의 baggage가 한도를 넘으면 잘라낸 baggage로 바꾼 context와 버린 항목 수를 반환
+func (p *baggageLimitingPropagator) limit(ctx context.Context) (context.Context, int) {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() <= p.maxMembers && len(bag.String()) <= p.maxBytes {
+		return ctx, 0
+	}
+
+	// 결과가 매번 같도록 키 순서로 남길 항목을 고른다
+	members := bag.Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+
+	var kept []baggage.Member
+	size := 0
+	for _, m := range members {
+		n := len(m.String())
+		if len(kept) > 0 {
+			n++ // 구분자 ','
+		}
+		if len(kept) >= p.maxMembers || size+n > p.maxBytes {
+			continue
+		}
+		kept = append(kept, m)
+		size += n
+	}
+
+	trimmed, err := baggage.New(kept...)
+	if err != nil {
+		log.Printf("baggage 자르기 실패: %v", err)
+		return ctx, 0
+	}
+
+	log.Printf("baggage가 한도를 넘어 잘라냈습니다: 항목 %d -> %d개", bag.Len(), trimmed.Len())
+	return baggage.ContextWithBaggage(ctx, trimmed), bag.Len() - trimmed.Len()
+}
+
+// Extract에서 잘라낸 baggage 항목 수를 담는 context 키
+type baggageDroppedKey struct{}
+
+func setBaggageTrimmed(span trace.Span, dropped int) {
+	span.SetAttributes(
+		attribute.Bool("baggage.trimmed", true),
+		attribute.Int("baggage.dropped_members", dropped),
+	)
+}
+
+// 들어온 요청의 baggage가 Extract에서 잘렸으면 서버 span에 baggage.trimmed를 기록하는 미들웨어
+// otelhttp 핸들러 안쪽(서버 span이 context에 있는 위치)에 둔다
+func WithBaggageTrimmed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if dropped, _ := r.Context().Value(baggageDroppedKey{}).(int); dropped > 0 {
+			setBaggageTrimmed(trace.SpanFromContext(r.Context()), dropped)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
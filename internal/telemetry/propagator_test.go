@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewPropagatorNames(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string // propagator가 다루는 헤더 (정렬)
+	}{
+		{"", []string{"baggage", "traceparent", "tracestate"}},
+		{"tracecontext", []string{"traceparent", "tracestate"}},
+		{"baggage", []string{"baggage"}},
+		{"b3", []string{"b3"}},
+		{"b3multi", []string{"x-b3-flags", "x-b3-sampled", "x-b3-spanid", "x-b3-traceid"}},
+		{" tracecontext , b3 ", []string{"b3", "traceparent", "tracestate"}},
+		// 알 수 없는 이름은 건너뛴다
+		{"tracecontext,jaeger", []string{"traceparent", "tracestate"}},
+		{"xray", nil},
+	}
+	for _, tt := range tests {
+		t.Setenv("OTEL_PROPAGATORS", tt.value)
+		got := newPropagator().Fields()
+		slices.Sort(got)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("OTEL_PROPAGATORS=%q: Fields() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
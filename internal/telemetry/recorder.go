@@ -0,0 +1,76 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"observability-playground/internal/config"
+)
+
+// InitTracer가 등록한 메모리 span 기록기 (비활성화되어 있으면 nil)
+var recentSpans *spanRecorder
+
+// TRACE_RECORDER_SIZE(기본값 100, 0이면 비활성화)로 메모리에 남길 span 수를 정한다
+func getTraceRecorderSize() int {
+	return config.NonNegativeInt("TRACE_RECORDER_SIZE", 100)
+}
+
+// 끝난 span을 최근 size개까지만 메모리에 보관하는 span processor
+// Tempo 없이도 /traces로 방금 만든 span을 확인할 수 있다
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan // 링 버퍼
+	next  int
+	full  bool
+}
+
+func newSpanRecorder(size int) *spanRecorder {
+	return &spanRecorder{spans: make([]sdktrace.ReadOnlySpan, size)}
+}
+
+func (r *spanRecorder) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (r *spanRecorder) OnEnd(s sdktrace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans[r.next] = s
+	r.next = (r.next + 1) % len(r.spans)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *spanRecorder) Shutdown(context.Context) error   { return nil }
+func (r *spanRecorder) ForceFlush(context.Context) error { return nil }
+
+// 보관 중인 span을 끝난 순서대로 반환
+func (r *spanRecorder) recent() []sdktrace.ReadOnlySpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]sdktrace.ReadOnlySpan(nil), r.spans[:r.next]...)
+	}
+	return append(append([]sdktrace.ReadOnlySpan(nil), r.spans[r.next:]...), r.spans[:r.next]...)
+}
+
+// 최근에 끝난 span을 JSON 배열로 돌려주는 /traces 핸들러
+// 이 핸들러 자체의 span이 섞이지 않도록 미들웨어 없이 등록한다
+// 리소스(호스트 이름, 프로세스 인자 등)까지 그대로 내보내므로 서비스는 DEBUG_ENDPOINTS=true일 때만 등록한다
+func TracesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if recentSpans == nil {
+			http.Error(w, "span 기록기가 비활성화되어 있습니다 (TRACE_RECORDER_SIZE=0).", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(tracetest.SpanStubsFromReadOnlySpans(recentSpans.recent()))
+	})
+}
@@ -0,0 +1,49 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"observability-playground/internal/config"
+)
+
+// OTLP gRPC exporter의 재시도 정책
+// OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL: 첫 재시도까지 대기 시간 (기본값 5s)
+// OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL: 재시도 간격 상한 (기본값 30s)
+// OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME: 한 batch를 포기하기까지의 총 시간 (기본값 1m)
+func getOTLPRetryConfig() otlptracegrpc.RetryConfig {
+	cfg := otlptracegrpc.RetryConfig{
+		Enabled:         true,
+		InitialInterval: config.PositiveDuration("OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL", 5*time.Second),
+		MaxInterval:     config.PositiveDuration("OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL", 30*time.Second),
+		MaxElapsedTime:  config.PositiveDuration("OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME", time.Minute),
+	}
+	if cfg.MaxInterval < cfg.InitialInterval {
+		log.Printf("OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL(%v)이 초기 간격(%v)보다 작아 초기 간격으로 맞춥니다.", cfg.MaxInterval, cfg.InitialInterval)
+		cfg.MaxInterval = cfg.InitialInterval
+	}
+	return cfg
+}
+
+// 재시도 정책과, 실패한 export 시도를 로그로 남기는 interceptor를 설정하는 옵션
+// exporter는 재시도 시점을 알려주지 않으므로 gRPC 호출마다 결과를 확인한다
+func otlpRetryOptions() []otlptracegrpc.Option {
+	return []otlptracegrpc.Option{
+		otlptracegrpc.WithRetry(getOTLPRetryConfig()),
+		otlptracegrpc.WithDialOption(grpc.WithUnaryInterceptor(logFailedExport)),
+	}
+}
+
+func logFailedExport(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		slog.Warn("OTLP export 시도 실패 (일시적인 오류면 재시도 정책에 따라 다시 시도)", "target", cc.Target(), "code", status.Code(err).String(), "error", err)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetOTLPRetryConfig(t *testing.T) {
+	tests := []struct {
+		name                  string
+		initial, max, elapsed string
+		wantInitial, wantMax  time.Duration
+		wantElapsed           time.Duration
+	}{
+		{"기본값", "", "", "", 5 * time.Second, 30 * time.Second, time.Minute},
+		{"직접 설정", "100ms", "2s", "10s", 100 * time.Millisecond, 2 * time.Second, 10 * time.Second},
+		{"상한이 초기 간격보다 작음", "3s", "1s", "", 3 * time.Second, 3 * time.Second, time.Minute},
+		{"잘못된 값", "soon", "-1s", "0s", 5 * time.Second, 30 * time.Second, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL", tt.initial)
+			t.Setenv("OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL", tt.max)
+			t.Setenv("OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME", tt.elapsed)
+			cfg := getOTLPRetryConfig()
+			if !cfg.Enabled || cfg.InitialInterval != tt.wantInitial || cfg.MaxInterval != tt.wantMax || cfg.MaxElapsedTime != tt.wantElapsed {
+				t.Errorf("getOTLPRetryConfig() = %+v, want 초기 %v 상한 %v 총 %v", cfg, tt.wantInitial, tt.wantMax, tt.wantElapsed)
+			}
+		})
+	}
+}
+
+func TestNewOTLPGRPCExporterWithCustomRetry(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "1500")
+	t.Setenv("OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL", "100ms")
+	t.Setenv("OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL", "1s")
+	t.Setenv("OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME", "5s")
+
+	if got := getOTLPTimeout(); got != 1500*time.Millisecond {
+		t.Errorf("getOTLPTimeout() = %v, want 1.5s", got)
+	}
+	// 연결은 span을 보낼 때 맺으므로 주소에 서버가 없어도 생성은 성공한다
+	exporter, err := newOTLPGRPCExporter(context.Background(), "localhost:4317")
+	if err != nil {
+		t.Fatalf("newOTLPGRPCExporter: %v", err)
+	}
+	exporter.Shutdown(context.Background())
+}
@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 기반 sampler가 샘플링하기로 한 span 중 초당 최대 개수만 통과시키는 sampler
+// 토큰 버킷으로 구현하며, 버킷 크기는 1초 분량이다
+// newSampler는 이것을 ParentBased의 루트 sampler로만 쓰므로 실제로는 초당 새 trace 수를 제한한다
+// (하위 span은 부모의 결정을 따르므로 trace 중간이 잘리지 않는다)
+type rateLimitingSampler struct {
+	base       sdktrace.Sampler
+	maxPerSec  float64
+	mu         sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRateLimitingSampler(base sdktrace.Sampler, maxPerSec float64) *rateLimitingSampler {
+	return &rateLimitingSampler{
+		base:       base,
+		maxPerSec:  maxPerSec,
+		tokens:     maxPerSec,
+		lastRefill: time.Now(),
+	}
+}
+
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.base.ShouldSample(p)
+	if result.Decision != sdktrace.RecordAndSample {
+		return result
+	}
+	if s.take() {
+		return result
+	}
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+// 토큰을 하나 꺼낸다. 남은 토큰이 없으면 false
+func (s *rateLimitingSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.tokens += now.Sub(s.lastRefill).Seconds() * s.maxPerSec
+	if s.tokens > s.maxPerSec {
+		s.tokens = s.maxPerSec
+	}
+	s.lastRefill = now
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimiting{%s,max=%g/s}", s.base.Description(), s.maxPerSec)
+}
+
+// 시작 시점 속성에 error=true가 있는 span은 기반 sampler의 결정과 상관없이 항상 샘플링하는 sampler
+// 비율 샘플링 때문에 에러 trace가 버려지지 않도록 한다
+// 샘플링은 span 시작 시 결정되므로 tracer.Start에 trace.WithAttributes로 넘긴 속성만 확인할 수 있다
+type errorSampler struct {
+	base sdktrace.Sampler
+}
+
+func (s errorSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, kv := range p.Attributes {
+		if kv.Key != "error" {
+			continue
+		}
+		if kv.Value.AsBool() || kv.Value.AsString() == "true" {
+			return sdktrace.SamplingResult{
+				Decision:   sdktrace.RecordAndSample,
+				Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s errorSampler) Description() string {
+	return fmt.Sprintf("AlwaysSampleErrors{%s}", s.base.Description())
+}
+
+// 샘플링 비율과 환경 변수로 sampler 구성
+// ratio: 새 trace를 샘플링할 비율 0.0~1.0 (config.Config.SampleRatio, 기본값 1.0)
+// MAX_TRACES_PER_SEC: 초당 샘플링할 최대 새 trace(루트 span) 수 (기본값 0 = 제한 없음)
+// 하위 span은 세지 않으므로 초당 span 수는 trace 크기만큼 더 많을 수 있다. 이전 이름 MAX_SPANS_PER_SEC도 받는다
+// 비율이 1.0이고 초당 제한도 없으면 기존처럼 AlwaysSample을 사용하고, 그 밖에는 하위 span이 부모의 결정을 따른다
+// 단, 시작할 때 error=true 속성을 가진 span은 부모가 버려졌거나 초당 제한을 넘었어도 항상 샘플링한다
+func newSampler(ratio float64) sdktrace.Sampler {
+	maxPerSec := getMaxTracesPerSec()
+
+	if ratio >= 1 && maxPerSec == 0 {
+		return sdktrace.AlwaysSample()
+	}
+
+	root := sdktrace.TraceIDRatioBased(ratio)
+	if maxPerSec > 0 {
+		root = newRateLimitingSampler(root, maxPerSec)
+	}
+	sampler := errorSampler{base: sdktrace.ParentBased(root)}
+	log.Printf("sampler 설정: %s", sampler.Description())
+	return sampler
+}
+
+// MAX_TRACES_PER_SEC 파싱 (없으면 이전 이름 MAX_SPANS_PER_SEC, 기본값 0 = 제한 없음)
+func getMaxTracesPerSec() float64 {
+	if os.Getenv("MAX_TRACES_PER_SEC") == "" && os.Getenv("MAX_SPANS_PER_SEC") != "" {
+		log.Println("MAX_SPANS_PER_SEC는 새 trace 수만 제한합니다. MAX_TRACES_PER_SEC를 사용하세요.")
+		return config.NonNegativeFloat("MAX_SPANS_PER_SEC", 0)
+	}
+	return config.NonNegativeFloat("MAX_TRACES_PER_SEC", 0)
+}
@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 새 trace의 루트 span 샘플링 파라미터
+func rootParams(attrs ...attribute.KeyValue) sdktrace.SamplingParameters {
+	return sdktrace.SamplingParameters{
+		ParentContext: context.Background(),
+		TraceID:       trace.TraceID{1},
+		Name:          "op",
+		Attributes:    attrs,
+	}
+}
+
+// 샘플링된(또는 버려진) 부모 아래 하위 span의 샘플링 파라미터
+func childParams(parentSampled bool) sdktrace.SamplingParameters {
+	var flags trace.TraceFlags
+	if parentSampled {
+		flags = trace.FlagsSampled
+	}
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: flags,
+	})
+	return sdktrace.SamplingParameters{
+		ParentContext: trace.ContextWithSpanContext(context.Background(), parent),
+		TraceID:       trace.TraceID{1},
+		Name:          "child",
+	}
+}
+
+func TestNewSamplerUsesParentBasedRatio(t *testing.T) {
+	t.Setenv("MAX_TRACES_PER_SEC", "")
+	t.Setenv("MAX_SPANS_PER_SEC", "")
+
+	if got := newSampler(1).Description(); got != "AlwaysOnSampler" {
+		t.Errorf("newSampler(1) = %s, want AlwaysOnSampler", got)
+	}
+	got := newSampler(0.25).Description()
+	if !strings.Contains(got, "ParentBased{root:TraceIDRatioBased{0.25}") {
+		t.Errorf("newSampler(0.25) = %s, want ParentBased(TraceIDRatioBased(0.25))", got)
+	}
+
+	// 비율 0이면 새 trace는 버리지만 error=true로 시작한 span은 샘플링한다
+	sampler := newSampler(0)
+	if d := sampler.ShouldSample(rootParams()).Decision; d != sdktrace.Drop {
+		t.Errorf("비율 0의 루트 span 결정 = %v, want Drop", d)
+	}
+	for _, errAttr := range []attribute.KeyValue{attribute.Bool("error", true), attribute.String("error", "true")} {
+		if d := sampler.ShouldSample(rootParams(errAttr)).Decision; d != sdktrace.RecordAndSample {
+			t.Errorf("비율 0의 %v span 결정 = %v, want RecordAndSample", errAttr, d)
+		}
+	}
+	if d := sampler.ShouldSample(childParams(false)).Decision; d != sdktrace.Drop {
+		t.Errorf("버려진 부모 아래 span 결정 = %v, want Drop", d)
+	}
+}
+
+func TestNewSamplerCapsNewTracesPerSecond(t *testing.T) {
+	t.Setenv("MAX_TRACES_PER_SEC", "2")
+	sampler := newSampler(1)
+
+	// 버킷에는 1초 분량(2개)만 있으므로 바로 이어진 새 trace 5개 중 2개만 샘플링
+	sampled := 0
+	for range 5 {
+		if sampler.ShouldSample(rootParams()).Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	if sampled != 2 {
+		t.Errorf("샘플링된 새 trace = %d, want 2", sampled)
+	}
+
+	// 제한은 루트 span에만 걸리므로 이미 샘플링된 trace의 하위 span과 에러 span은 계속 샘플링한다
+	if d := sampler.ShouldSample(childParams(true)).Decision; d != sdktrace.RecordAndSample {
+		t.Errorf("제한 초과 후 샘플링된 부모 아래 span 결정 = %v, want RecordAndSample", d)
+	}
+	if d := sampler.ShouldSample(rootParams(attribute.Bool("error", true))).Decision; d != sdktrace.RecordAndSample {
+		t.Errorf("제한 초과 후 에러 span 결정 = %v, want RecordAndSample", d)
+	}
+}
+
+func TestNewSamplerAcceptsOldMaxSpansPerSecName(t *testing.T) {
+	t.Setenv("MAX_TRACES_PER_SEC", "")
+	t.Setenv("MAX_SPANS_PER_SEC", "3")
+	if got := newSampler(1).Description(); !strings.Contains(got, "max=3/s") {
+		t.Errorf("newSampler(1) = %s, want 초당 3개 제한", got)
+	}
+}
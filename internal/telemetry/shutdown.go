@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"observability-playground/internal/config"
+)
+
+// SHUTDOWN_TIMEOUT(기본값 5초) 안에 남은 데이터를 내보내고 provider를 종료한다
+// shutdown에는 TracerProvider.Shutdown, MeterProvider.Shutdown 등을 넘긴다
+// 수집기에 연결할 수 없어도 프로세스가 종료 단계에서 멈추지 않도록 기다리는 시간을 제한한다
+func Shutdown(name string, shutdown func(context.Context) error) {
+	timeout := config.PositiveDuration("SHUTDOWN_TIMEOUT", 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := shutdown(ctx)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Printf("%s 종료 시간 초과 (%v), 내보내지 못한 데이터는 버려집니다: %v", name, timeout, err)
+	case err != nil:
+		log.Printf("%s 종료 실패: %v", name, err)
+	default:
+		log.Printf("%s 종료 완료 (남은 데이터 전송 완료)", name)
+	}
+}
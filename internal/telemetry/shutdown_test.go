@@ -0,0 +1,32 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 종료에 오래 걸리는 exporter (수집기에 연결할 수 없는 상황 흉내)
+type slowShutdownExporter struct{ noopExporter }
+
+func (slowShutdownExporter) Shutdown(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(10 * time.Second):
+		return nil
+	}
+}
+
+func TestShutdownRespectsTimeout(t *testing.T) {
+	t.Setenv("SHUTDOWN_TIMEOUT", "50ms")
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(slowShutdownExporter{}))
+
+	start := time.Now()
+	Shutdown("TracerProvider", tp.Shutdown)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown이 %v 동안 기다렸습니다 (SHUTDOWN_TIMEOUT 50ms)", elapsed)
+	}
+}
@@ -0,0 +1,228 @@
+// telemetry 패키지는 모든 서비스가 공유하는 OpenTelemetry 초기화 코드를 담는다.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"observability-playground/internal/config"
+)
+
+// 마지막으로 InitTracer가 TracerProvider에 설정한 리소스
+var tracerResource *resource.Resource
+
+// InitTracer가 TracerProvider에 설정한 리소스 (초기화 전이면 nil)
+func Resource() *resource.Resource {
+	return tracerResource
+}
+
+// 서비스별로 InitTracer 동작을 조정하는 옵션
+type Option func(*tracerConfig)
+
+type tracerConfig struct {
+	wrapExporter func(sdktrace.SpanExporter) sdktrace.SpanExporter
+	processors   []sdktrace.SpanProcessor
+	idGenerator  sdktrace.IDGenerator
+}
+
+// 주 exporter를 batch processor에 넘기기 전에 감싼다 (export 결과 추적 등)
+func WithExporterWrapper(wrap func(sdktrace.SpanExporter) sdktrace.SpanExporter) Option {
+	return func(c *tracerConfig) {
+		c.wrapExporter = wrap
+	}
+}
+
+// batch processor보다 먼저 실행되는 span processor를 추가한다
+func WithSpanProcessor(sp sdktrace.SpanProcessor) Option {
+	return func(c *tracerConfig) {
+		c.processors = append(c.processors, sp)
+	}
+}
+
+// 기본(무작위) ID 생성기 대신 gen을 사용한다
+// TRACE_ID_FORMAT, FIXED_TRACE_ID보다 우선하며, 테스트에서 ID를 예측해야 할 때 쓴다
+func WithIDGenerator(gen sdktrace.IDGenerator) Option {
+	return func(c *tracerConfig) {
+		c.idGenerator = gen
+	}
+}
+
+// TracerProvider를 만들어 전역으로 등록하고 propagator를 설정한다
+// cfg의 TempoEndpoint(기본값 tempo:4317)로 OTLP 전송하고 SampleRatio로 샘플링하며, 그 밖의 동작은 환경 변수로 조정한다
+func InitTracer(ctx context.Context, cfg config.Config, options ...Option) (*sdktrace.TracerProvider, error) {
+	tc := tracerConfig{}
+	for _, opt := range options {
+		opt(&tc)
+	}
+
+	// span exporter 생성 (기본값: Tempo로 OTLP 전송)
+	// 감싸기 옵션은 목록의 첫 번째(주) exporter에만 적용한다
+	exporters, err := newSpanExporters(ctx, cfg.TempoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	for i := range exporters {
+		exporters[i] = newSplittingExporter(exporters[i])
+	}
+	if tc.wrapExporter != nil && len(exporters) > 0 {
+		exporters[0] = tc.wrapExporter(exporters[0])
+	}
+
+	res, err := newResource(ctx, cfg.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	tracerResource = res
+
+	// TracerProvider 설정
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(newSampler(cfg.SampleRatio)),
+		// span 하나가 가질 수 있는 속성 수와 속성 값 길이 제한 (getSpanLimits)
+		sdktrace.WithRawSpanLimits(getSpanLimits()),
+	}
+	for _, sp := range tc.processors {
+		opts = append(opts, sdktrace.WithSpanProcessor(sp))
+	}
+	// 핸들러 span을 모아 미들웨어가 응답 상태 코드를 기록할 수 있게 한다 (WithHandlerSpans)
+	opts = append(opts, sdktrace.WithSpanProcessor(handlerSpanProcessor{}))
+	// TRACE_RECORDER_SIZE개의 최근 span을 메모리에 남겨 /traces로 노출
+	// 0이면 이전 초기화에서 남은 기록기도 비운다
+	recentSpans = nil
+	if size := getTraceRecorderSize(); size > 0 {
+		recentSpans = newSpanRecorder(size)
+		opts = append(opts, sdktrace.WithSpanProcessor(recentSpans))
+	}
+	// exporter마다 별도의 batch processor를 두어 모든 exporter로 span을 보낸다
+	// TRACE_IGNORE_ROUTES의 경로는 batch processor에 넘기기 전에 걸러낸다
+	ignoredRoutes := getIgnoredRoutes()
+	for _, exporter := range exporters {
+		opts = append(opts, sdktrace.WithSpanProcessor(
+			newRouteFilterProcessor(sdktrace.NewBatchSpanProcessor(exporter), ignoredRoutes),
+		))
+	}
+	opts = append(opts, sdktrace.WithResource(res))
+
+	// TRACE_ID_FORMAT=xray이면 X-Ray 호환 ID 생성기와 전파기 사용 (기본값: W3C 형식)
+	xrayFormat := os.Getenv("TRACE_ID_FORMAT") == "xray"
+	if xrayFormat {
+		opts = append(opts, sdktrace.WithIDGenerator(xray.NewIDGenerator()))
+	}
+
+	// FIXED_TRACE_ID가 설정되면 모든 trace에 같은 ID 사용 (데모 전용)
+	if gen := newFixedTraceIDGenerator(os.Getenv("FIXED_TRACE_ID")); gen != nil {
+		opts = append(opts, sdktrace.WithIDGenerator(gen))
+	}
+	if tc.idGenerator != nil {
+		opts = append(opts, sdktrace.WithIDGenerator(tc.idGenerator))
+	}
+
+	// 보조 OTLP 엔드포인트가 있으면 별도의 batch processor로 동시에 전송
+	// 각 processor는 독립적으로 동작하므로 한쪽 실패가 다른 쪽에 영향을 주지 않는다
+	if secondaryEndpoint := os.Getenv("SECONDARY_OTEL_ENDPOINT"); secondaryEndpoint != "" {
+		// 주 exporter와 같은 gRPC 설정(TLS, 제한 시간, 재시도)과 배치 분할을 적용한다
+		secondaryExporter, err := newOTLPGRPCExporter(ctx, secondaryEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("보조 OTLP exporter 생성 실패: %w", err)
+		}
+		secondaryExporter = newSplittingExporter(secondaryExporter)
+		opts = append(opts, sdktrace.WithSpanProcessor(
+			newRouteFilterProcessor(sdktrace.NewBatchSpanProcessor(secondaryExporter), ignoredRoutes),
+		))
+		log.Printf("보조 OTLP 엔드포인트 %s로도 span을 전송합니다.", secondaryEndpoint)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+
+	// OTEL_PROPAGATORS로 고른 형식(기본값: W3C trace context와 baggage)으로 서비스 간에 전파
+	// baggage는 크기 제한 적용
+	propagator := newPropagator()
+	// X-Ray 형식이면 X-Amzn-Trace-Id 전파기를 추가하되 OTEL_PROPAGATORS의 전파기(baggage 등)는 그대로 둔다
+	// 두 헤더가 모두 오면 X-Ray 헤더의 trace context를 쓰도록 X-Ray를 마지막에 둔다
+	if xrayFormat {
+		propagator = propagation.NewCompositeTextMapPropagator(propagator, xray.Propagator{})
+		log.Println("X-Ray 형식의 trace ID와 전파기를 사용합니다.")
+	}
+	// DISABLE_PROPAGATION=true이면 아무것도 전파하지 않는 propagator 사용 (격리 테스트용)
+	// 들어오는 trace context를 무시하고 나가는 요청에도 싣지 않으므로
+	// 서비스마다 항상 새 trace가 시작되어 sender와 receiver의 span이 서로 다른 trace로 나뉜다
+	if disabled, _ := strconv.ParseBool(os.Getenv("DISABLE_PROPAGATION")); disabled {
+		propagator = propagation.NewCompositeTextMapPropagator()
+		log.Println("trace context 전파를 비활성화합니다. 모든 요청이 새 trace로 시작됩니다.")
+	}
+	otel.SetTextMapPropagator(newBaggageLimitingPropagator(propagator))
+
+	return tp, nil
+}
+
+// 속성 값 길이 제한의 기본값 (SDK 기본값은 무제한)
+const defaultAttributeValueLengthLimit = 4096
+
+// span 속성 제한 설정
+// OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT 등 표준 환경 변수는 SDK가 읽으며(속성 수 기본값 128, -1은 무제한),
+// 값 길이는 OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT(없으면 OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT)가 없을 때 4096자로 제한한다
+// 핸들러가 큰 본문 등을 속성에 넣어도 span 하나가 메모리를 무한히 차지하지 않게 하기 위함
+func getSpanLimits() sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+	if os.Getenv("OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT") == "" && os.Getenv("OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT") == "" {
+		limits.AttributeValueLengthLimit = defaultAttributeValueLengthLimit
+	}
+	return limits
+}
+
+// 배포 환경 이름으로 예상하는 값
+var knownEnvironments = map[string]bool{"dev": true, "staging": true, "prod": true}
+
+// ENVIRONMENT(없으면 DEPLOY_ENV, 기본값 dev)에서 배포 환경 이름을 읽는다
+// dev, staging, prod 이외의 값은 경고만 남기고 그대로 사용한다
+func getEnvironment() string {
+	environment := os.Getenv("ENVIRONMENT")
+	if environment == "" {
+		environment = os.Getenv("DEPLOY_ENV")
+	}
+	if environment == "" {
+		return "dev"
+	}
+	if !knownEnvironments[environment] {
+		log.Printf("알 수 없는 배포 환경 %q (dev, staging, prod 중 하나가 아님), 그대로 사용합니다.", environment)
+	}
+	return environment
+}
+
+// 서비스 이름, 빌드 버전 등 trace와 메트릭에 공통으로 붙는 리소스 생성
+// 호스트 이름, 프로세스 정보, 컨테이너 ID도 감지해 함께 기록한다
+// 배포 환경은 기존 environment 속성과 표준 deployment.environment 속성에 함께 기록한다
+func newResource(ctx context.Context, serviceName string) (*resource.Resource, error) {
+	environment := getEnvironment()
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			attribute.String("environment", environment),
+			semconv.DeploymentEnvironmentKey.String(environment),
+		),
+		resource.WithAttributes(ReadBuildInfo().Attributes()...),
+		resource.WithHost(),
+		resource.WithProcess(),
+		resource.WithContainer(),
+	)
+	if errors.Is(err, resource.ErrPartialResource) {
+		// 일부 감지기만 실패한 경우 얻은 속성으로 계속 진행
+		log.Printf("일부 리소스 속성을 감지하지 못했습니다: %v", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("리소스 생성 실패: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,198 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+
+	"observability-playground/internal/config"
+)
+
+func TestInitTracerSetsServiceName(t *testing.T) {
+	tp, exporter := newTestTracerProvider(t)
+	if tp == nil {
+		t.Fatal("InitTracer가 nil provider를 반환했습니다")
+	}
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("span 수 = %d, want 1", len(spans))
+	}
+	got, ok := spans[0].Resource.Set().Value(semconv.ServiceNameKey)
+	if !ok || got.AsString() != "test" {
+		t.Errorf("리소스 service.name = %v (있음: %v), want test", got.AsString(), ok)
+	}
+}
+
+func TestNewResourceDetectsHostName(t *testing.T) {
+	res, err := newResource(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("newResource: %v", err)
+	}
+	if got, ok := res.Set().Value(semconv.HostNameKey); !ok || got.AsString() == "" {
+		t.Errorf("리소스 host.name = %q (있음: %v), want 비어 있지 않은 값", got.AsString(), ok)
+	}
+}
+
+func TestNewResourceCarriesServiceVersion(t *testing.T) {
+	prev := Version
+	Version = "v1.2.3"
+	t.Cleanup(func() { Version = prev })
+
+	res, err := newResource(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("newResource: %v", err)
+	}
+	if got, _ := res.Set().Value(semconv.ServiceVersionKey); got.AsString() != "v1.2.3" {
+		t.Errorf("리소스 service.version = %q, want v1.2.3", got.AsString())
+	}
+}
+
+func TestNewResourceEnvironment(t *testing.T) {
+	tests := []struct {
+		name, environment, deployEnv, want string
+	}{
+		{"기본값", "", "", "dev"},
+		{"ENVIRONMENT", "prod", "", "prod"},
+		{"DEPLOY_ENV", "", "staging", "staging"},
+		{"ENVIRONMENT 우선", "prod", "staging", "prod"},
+		{"알 수 없는 값도 그대로", "qa", "", "qa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENVIRONMENT", tt.environment)
+			t.Setenv("DEPLOY_ENV", tt.deployEnv)
+			res, err := newResource(context.Background(), "test")
+			if err != nil {
+				t.Fatalf("newResource: %v", err)
+			}
+			for _, key := range []attribute.Key{"environment", semconv.DeploymentEnvironmentKey} {
+				if got, _ := res.Set().Value(key); got.AsString() != tt.want {
+					t.Errorf("리소스 %s = %q, want %q", key, got.AsString(), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestInitTracerFansOutToAllExporters(t *testing.T) {
+	// OTLP/HTTP와 Zipkin 수집기 대신 받은 요청 수만 세는 서버를 띄운다
+	var otlpRequests, zipkinRequests atomic.Int32
+	otlp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otlpRequests.Add(1)
+	}))
+	defer otlp.Close()
+	zipkin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zipkinRequests.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer zipkin.Close()
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp, zipkin, otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "")
+	t.Setenv("ZIPKIN_ENDPOINT", zipkin.URL)
+	t.Setenv("TRACE_RECORDER_SIZE", "0")
+
+	ctx := context.Background()
+	cfg := config.Config{ServiceName: "test", SampleRatio: 1, TempoEndpoint: strings.TrimPrefix(otlp.URL, "http://")}
+	tp, err := InitTracer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("InitTracer: %v", err)
+	}
+	defer tp.Shutdown(ctx)
+
+	_, span := tp.Tracer("test").Start(ctx, "op")
+	span.End()
+	if err := tp.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	// 목록에 두 번 나온 otlp는 exporter 하나만 만든다
+	if got := otlpRequests.Load(); got != 1 {
+		t.Errorf("OTLP 수집기가 받은 요청 = %d, want 1", got)
+	}
+	if got := zipkinRequests.Load(); got != 1 {
+		t.Errorf("Zipkin 수집기가 받은 요청 = %d, want 1", got)
+	}
+}
+
+// export할 때마다 배치 크기를 기록하는 exporter
+type batchSizeExporter struct {
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (e *batchSizeExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sizes = append(e.sizes, len(spans))
+	return nil
+}
+
+func (e *batchSizeExporter) Shutdown(context.Context) error { return nil }
+
+func TestInitTracerAppliesBSPEnv(t *testing.T) {
+	// batch processor는 SDK가 OTEL_BSP_* 환경 변수를 읽어 설정한다
+	t.Setenv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", "1")
+	t.Setenv("OTEL_BSP_SCHEDULE_DELAY", "60000") // 주기 export 대신 ForceFlush로만 내보낸다
+	batches := &batchSizeExporter{}
+	tp, _ := newTestTracerProvider(t, WithExporterWrapper(func(sdktrace.SpanExporter) sdktrace.SpanExporter {
+		return batches
+	}))
+
+	ctx := context.Background()
+	for range 3 {
+		_, span := tp.Tracer("test").Start(ctx, "op")
+		span.End()
+	}
+	if err := tp.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	batches.mu.Lock()
+	defer batches.mu.Unlock()
+	if want := []int{1, 1, 1}; !slices.Equal(batches.sizes, want) {
+		t.Errorf("배치 크기 = %v, want %v", batches.sizes, want)
+	}
+}
+
+func TestInitTracerAppliesSpanLimits(t *testing.T) {
+	t.Setenv("OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT", "2")
+	t.Setenv("OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT", "")
+	t.Setenv("OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT", "")
+	tp, exporter := newTestTracerProvider(t)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "op")
+	span.SetAttributes(
+		attribute.String("a", "1"),
+		attribute.String("long", strings.Repeat("x", defaultAttributeValueLengthLimit+100)),
+		attribute.String("c", "3"),
+		attribute.String("d", "4"),
+	)
+	span.End()
+
+	got := exporter.GetSpans()[0]
+	// 한도를 넘은 속성은 버리고 버린 개수를 기록한다
+	if len(got.Attributes) != 2 || got.DroppedAttributes != 2 {
+		t.Errorf("속성 %d개, 버림 %d개, want 2개와 2개", len(got.Attributes), got.DroppedAttributes)
+	}
+	// 값 길이는 환경 변수가 없으면 기본 한도로 자른다
+	for _, kv := range got.Attributes {
+		if kv.Key == "long" && len(kv.Value.AsString()) != defaultAttributeValueLengthLimit {
+			t.Errorf("long 속성 길이 = %d, want %d", len(kv.Value.AsString()), defaultAttributeValueLengthLimit)
+		}
+	}
+}
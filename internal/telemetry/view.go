@@ -0,0 +1,43 @@
+package telemetry
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// 요청 처리 시간 히스토그램의 기본 버킷 경계 (ms)
+// /slow의 100~2000ms 구간을 충분히 나눠 볼 수 있도록 잡았다
+var defaultLatencyBucketsMS = []float64{50, 100, 250, 500, 1000, 2000, 5000}
+
+// LATENCY_BUCKETS_MS(쉼표 구분, 오름차순 ms 값) 파싱 (없거나 잘못된 값이면 기본 버킷)
+func getLatencyBuckets() []float64 {
+	v := os.Getenv("LATENCY_BUCKETS_MS")
+	if v == "" {
+		return defaultLatencyBucketsMS
+	}
+
+	var buckets []float64
+	for _, part := range strings.Split(v, ",") {
+		b, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || b < 0 || (len(buckets) > 0 && b <= buckets[len(buckets)-1]) {
+			log.Printf("잘못된 LATENCY_BUCKETS_MS 값 %q, 기본 버킷 %v를 사용합니다.", v, defaultLatencyBucketsMS)
+			return defaultLatencyBucketsMS
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets
+}
+
+// 요청 처리 시간 히스토그램에 명시적인 버킷 경계를 적용하는 view
+func newLatencyView() sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Name: RequestDurationInstrument},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+			Boundaries: getLatencyBuckets(),
+		}},
+	)
+}
@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetLatencyBuckets(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want []float64
+	}{
+		{"기본값", "", defaultLatencyBucketsMS},
+		{"사용자 지정", "10, 100,1000", []float64{10, 100, 1000}},
+		{"오름차순이 아님", "100,50", defaultLatencyBucketsMS},
+		{"숫자가 아님", "10,abc", defaultLatencyBucketsMS},
+		{"음수", "-1,10", defaultLatencyBucketsMS},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LATENCY_BUCKETS_MS", tt.env)
+			if got := getLatencyBuckets(); !slices.Equal(got, tt.want) {
+				t.Errorf("getLatencyBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
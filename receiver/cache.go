@@ -0,0 +1,111 @@
+package main
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+)
+
+// 고정 크기 LRU 캐시
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // 앞쪽일수록 최근에 사용
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value string
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).value, true
+}
+
+func (c *lruCache) add(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// /cached 핸들러가 사용하는 캐시 (CACHE_SIZE, 기본값 100)
+var valueCache *lruCache
+
+func initCache() {
+	size := config.PositiveInt("CACHE_SIZE", 100)
+	valueCache = newLRUCache(size)
+	log.Printf("LRU 캐시 크기: %d", size)
+}
+
+// 캐시 적중 여부를 span에 기록하는 핸들러
+// key 쿼리가 없으면 작은 키 집합에서 무작위로 골라 적중과 실패가 섞이게 한다
+func cachedHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "cached-handler")
+	defer span.End()
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		key = fmt.Sprintf("item-%d", rand.Intn(20))
+	}
+
+	value, hit := valueCache.get(key)
+	if !hit {
+		// 원본 조회를 흉내 내는 지연 후 캐시에 저장
+		time.Sleep(time.Duration(20+rand.Intn(80)) * time.Millisecond)
+		value = fmt.Sprintf("value-of-%s@%s", key, time.Now().Format(time.RFC3339))
+		valueCache.add(key, value)
+	}
+
+	span.SetAttributes(
+		attribute.String("cache.key", key),
+		attribute.Bool("cache.hit", hit),
+		attribute.Int("cache.size", valueCache.len()),
+	)
+
+	service.LogWithTrace(ctx, "캐시 요청", "key", key, "hit", hit)
+	fmt.Fprintf(w, "key=%s hit=%t value=%s\n", key, hit, value)
+}
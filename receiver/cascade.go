@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/service"
+)
+
+// 다운스트림 호출에 쓰는 공용 클라이언트 (initHandlers에서 한 번 만들어 연결을 재사용)
+var downstreamClient *http.Client
+
+// 연쇄 장애의 중간 단계 핸들러
+// fail_at=receiver이면 여기서 실패하고, 아니면 다운스트림(CASCADE_DOWNSTREAM_URL)을 호출한다
+// CASCADE_DOWNSTREAM_URL이 없으면 port로 자기 자신의 다운스트림 핸들러를 호출한다
+func cascadeHandler(port int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "cascade-handler")
+		defer span.End()
+
+		failAt := r.URL.Query().Get("fail_at")
+		span.SetAttributes(attribute.String("cascade.fail_at", failAt))
+
+		service.LogWithTrace(ctx, "연쇄 요청 수신", "method", r.Method, "path", r.URL.Path, "fail_at", failAt)
+
+		if failAt == "receiver" {
+			slog.Error("연쇄 장애 발생", "at", "receiver")
+			span.SetStatus(codes.Error, "receiver에서 장애 발생")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "receiver: 장애 발생\n")
+			return
+		}
+
+		downstreamURL := os.Getenv("CASCADE_DOWNSTREAM_URL")
+		if downstreamURL == "" {
+			downstreamURL = fmt.Sprintf("http://localhost:%d/cascade/downstream", port)
+		}
+
+		reqURL := fmt.Sprintf("%s?fail_at=%s", downstreamURL, url.QueryEscape(failAt))
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			slog.Error("다운스트림 요청 생성 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "다운스트림 요청 생성 실패")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		resp, err := downstreamClient.Do(req)
+		if err != nil {
+			slog.Error("다운스트림 요청 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "다운스트림 호출 실패")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "receiver: 다운스트림 호출 실패: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		span.SetAttributes(attribute.Int("cascade.downstream_status", resp.StatusCode))
+
+		// 다운스트림의 실패를 이 단계의 실패로 전파
+		if resp.StatusCode >= http.StatusInternalServerError {
+			slog.Warn("연쇄 장애 전파", "downstream_status", resp.StatusCode)
+			span.SetStatus(codes.Error, fmt.Sprintf("다운스트림 응답 %d", resp.StatusCode))
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "receiver: 하위 단계 실패\n%s", body)
+			return
+		}
+
+		fmt.Fprintf(w, "receiver: 연쇄 요청 성공\n%s", body)
+	}
+}
+
+// 연쇄 장애의 마지막 단계 핸들러 (fail_at=downstream이면 실패)
+func cascadeDownstreamHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "cascade-downstream-handler")
+	defer span.End()
+
+	failAt := r.URL.Query().Get("fail_at")
+	span.SetAttributes(attribute.String("cascade.fail_at", failAt))
+
+	service.LogWithTrace(ctx, "다운스트림 요청 수신", "method", r.Method, "path", r.URL.Path, "fail_at", failAt)
+
+	if failAt == "downstream" {
+		slog.Error("연쇄 장애 발생", "at", "downstream")
+		span.SetStatus(codes.Error, "다운스트림에서 장애 발생")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "downstream: 장애 발생\n")
+		return
+	}
+
+	fmt.Fprintf(w, "downstream: 정상 처리\n")
+}
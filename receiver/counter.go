@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	_ "modernc.org/sqlite"
+
+	"observability-playground/internal/service"
+)
+
+// 요청 수를 저장하는 SQLite DB (otelsql로 감싸 쿼리마다 하위 span이 생긴다)
+var counterDB *sql.DB
+
+// COUNTER_DB_PATH(기본값 :memory:)에 카운터 DB를 열고 테이블을 준비
+func initCounterDB() error {
+	path := os.Getenv("COUNTER_DB_PATH")
+	if path == "" {
+		path = ":memory:" // 기본값: 프로세스가 끝나면 사라지는 메모리 DB
+	}
+
+	db, err := otelsql.Open("sqlite", path,
+		otelsql.WithAttributes(attribute.String("db.system", "sqlite")),
+	)
+	if err != nil {
+		return fmt.Errorf("카운터 DB 열기 실패: %w", err)
+	}
+	// 메모리 DB는 연결마다 따로 생기므로 연결을 하나만 사용
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS request_counter (id INTEGER PRIMARY KEY, value INTEGER NOT NULL)`); err != nil {
+		db.Close()
+		return fmt.Errorf("카운터 테이블 생성 실패: %w", err)
+	}
+	if _, err := db.Exec(`INSERT OR IGNORE INTO request_counter (id, value) VALUES (1, 0)`); err != nil {
+		db.Close()
+		return fmt.Errorf("카운터 초기화 실패: %w", err)
+	}
+
+	counterDB = db
+	log.Printf("카운터 DB: %s", path)
+	return nil
+}
+
+// 요청마다 DB의 카운터를 1 증가시키는 미들웨어
+// 상태 확인처럼 세지 않을 경로도 있으므로 withRateLimit처럼 newMux에서 필요한 핸들러에만 감싼다
+// handle의 동시 처리 제한과 요청 제한 시간 안쪽에서 실행되며, 요청 context를 넘기므로 UPDATE span이 서버 span 아래에 생긴다
+func withCounterIncrement(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := counterDB.ExecContext(r.Context(), `UPDATE request_counter SET value = value + 1 WHERE id = 1`); err != nil {
+			slog.ErrorContext(r.Context(), "카운터 증가 실패", "error", err)
+		}
+		next(w, r)
+	}
+}
+
+// 현재 카운터 값을 돌려주는 핸들러
+func countHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "count-handler")
+	defer span.End()
+
+	var value int64
+	if err := counterDB.QueryRowContext(ctx, `SELECT value FROM request_counter WHERE id = 1`).Scan(&value); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "카운터 조회 실패")
+		slog.ErrorContext(ctx, "카운터 조회 실패", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "카운터 조회 실패: %v\n", err)
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("counter.value", value))
+	service.LogWithTrace(ctx, "카운터 조회", "value", value)
+	fmt.Fprintf(w, "요청 수: %d\n", value)
+}
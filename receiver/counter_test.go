@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCountHandlerQueriesDBInChildSpan(t *testing.T) {
+	t.Setenv("COUNTER_DB_PATH", "")
+	t.Setenv("SLOW_MIN_MS", "1")
+	t.Setenv("SLOW_MAX_MS", "1")
+	h := newHarness(t)
+
+	// 상태 확인은 세지 않고 데모 경로만 센다
+	h.get(t, "/health")
+	h.get(t, "/slow")
+	resp, body := h.get(t, "/count")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "요청 수: 1") {
+		t.Errorf("GET /count = %d %q, want 요청 수: 1", resp.StatusCode, body)
+	}
+
+	handler := h.span(t, "count-handler")
+	var query bool
+	for _, s := range h.spans() {
+		if s.Name != "sql.conn.query" || s.Parent.SpanID() != handler.SpanContext.SpanID() {
+			continue
+		}
+		query = true
+		if stmt, _ := spanAttr(s, "db.statement"); !strings.HasPrefix(stmt.(string), "SELECT") {
+			t.Errorf("sql.conn.query span db.statement = %v, want SELECT 문", stmt)
+		}
+	}
+	if !query {
+		t.Error("count-handler 아래에 sql.conn.query span이 없습니다")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+)
+
+// 요청 하나가 소모할 수 있는 최대 CPU 시간
+var cpuBurnMax = 1000 * time.Millisecond
+
+// ?ms=N 만큼 실제로 CPU를 소모하는 핸들러 (/slow의 sleep과 달리 CPU 포화를 재현)
+func cpuBurnHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "cpu-handler")
+	defer span.End()
+
+	ms, err := strconv.Atoi(r.URL.Query().Get("ms"))
+	if err != nil || ms <= 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "ms 쿼리 파라미터에 양의 정수를 지정하세요.\n")
+		return
+	}
+
+	target := time.Duration(ms) * time.Millisecond
+	if target > cpuBurnMax {
+		service.LogWithTrace(ctx, "요청한 CPU 시간이 최대값을 넘어 제한합니다", "requested", target, "max", cpuBurnMax)
+		target = cpuBurnMax
+	}
+	span.SetAttributes(attribute.Int64("cpu.requested_ms", target.Milliseconds()))
+
+	service.LogWithTrace(ctx, "CPU 소모 요청", "target", target)
+
+	// 스레드 단위 CPU 시간을 재기 위해 현재 스레드에 고정
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start := threadCPUTime()
+	wallStart := time.Now()
+	// 스레드가 CPU를 거의 받지 못할 때를 대비해 벽시계 기준 상한도 둔다
+	wallLimit := 10 * target
+
+	// 스레드 CPU 시간을 잴 수 없는 OS에서는 벽시계 시간만큼 소모한다
+	if !threadCPUTimeSupported {
+		wallLimit = target
+	}
+
+	var x uint64
+	for (!threadCPUTimeSupported || threadCPUTime()-start < target) && time.Since(wallStart) < wallLimit {
+		for i := 0; i < 100000; i++ {
+			x += uint64(i) * 2654435761
+		}
+	}
+	consumed := threadCPUTime() - start
+	elapsed := time.Since(wallStart)
+
+	span.SetAttributes(attribute.Float64("cpu.wall_ms", float64(elapsed.Microseconds())/1000))
+	if threadCPUTimeSupported {
+		span.SetAttributes(attribute.Float64("cpu.consumed_ms", float64(consumed.Microseconds())/1000))
+	}
+
+	fmt.Fprintf(w, "CPU 소모 완료! CPU 시간: %v, 경과 시간: %v (checksum %d)\n",
+		consumed.Round(time.Millisecond), elapsed.Round(time.Millisecond), x%10)
+}
+
+// CPU_BURN_MAX_MS 환경 변수로 최대 CPU 소모 시간 설정 (기본값 1000ms)
+func initCPUBurnMax() {
+	cpuBurnMax = time.Duration(config.PositiveInt("CPU_BURN_MAX_MS", 1000)) * time.Millisecond
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// 리눅스에서는 RUSAGE_THREAD로 스레드 단위 CPU 시간을 잴 수 있다
+const threadCPUTimeSupported = true
+
+// 현재 OS 스레드가 사용한 CPU 시간 (user + system)
+func threadCPUTime() time.Duration {
+	var ru unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_THREAD, &ru); err != nil {
+		return 0
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
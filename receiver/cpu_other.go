@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import "time"
+
+// RUSAGE_THREAD는 리눅스 전용이라 다른 OS에서는 스레드 CPU 시간을 잴 수 없다
+const threadCPUTimeSupported = false
+
+// 스레드 CPU 시간을 잴 수 없으므로 항상 0을 반환 (호출 측은 벽시계 기준으로 동작)
+func threadCPUTime() time.Duration {
+	return 0
+}
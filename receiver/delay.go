@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// /slow 지연 시간 분포 (main에서 초기화)
+var delayDistribution = "uniform"
+
+// DELAY_DISTRIBUTION(uniform, normal, exponential, 기본값 uniform) 파싱
+func getDelayDistribution() string {
+	v := os.Getenv("DELAY_DISTRIBUTION")
+	switch v {
+	case "":
+		return "uniform"
+	case "uniform", "normal", "exponential":
+		log.Printf("/slow 지연 시간 분포: %s (%d~%dms)", v, slowMinMS, slowMaxMS)
+		return v
+	default:
+		log.Printf("잘못된 DELAY_DISTRIBUTION 값 %q, 기본값 uniform을 사용합니다.", v)
+		return "uniform"
+	}
+}
+
+// slowMinMS~slowMaxMS 범위에서 delayDistribution에 따라 지연 시간(ms)을 뽑는다
+// normal: 범위 가운데를 평균으로, 범위의 1/6을 표준편차로 하는 정규분포 (범위를 벗어나면 잘라낸다)
+// exponential: slowMinMS부터 시작해 평균이 범위의 1/3인 지수분포 (대부분 빠르고 가끔 느린 실제 트래픽에 가깝다, 최댓값에서 자른다)
+func slowDelay() int {
+	width := float64(slowMaxMS - slowMinMS)
+	if width <= 0 {
+		return slowMinMS
+	}
+
+	var offset float64
+	switch delayDistribution {
+	case "normal":
+		offset = width/2 + rand.NormFloat64()*width/6
+	case "exponential":
+		offset = rand.ExpFloat64() * width / 3
+	default:
+		offset = rand.Float64() * width
+	}
+	offset = math.Max(0, math.Min(width, offset))
+	return slowMinMS + int(offset)
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestGetDelayDistribution(t *testing.T) {
+	for value, want := range map[string]string{
+		"":            "uniform",
+		"uniform":     "uniform",
+		"normal":      "normal",
+		"exponential": "exponential",
+		"pareto":      "uniform",
+	} {
+		t.Setenv("DELAY_DISTRIBUTION", value)
+		if got := getDelayDistribution(); got != want {
+			t.Errorf("DELAY_DISTRIBUTION=%q: %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestSlowDelayDistributions(t *testing.T) {
+	prevMin, prevMax, prevDist := slowMinMS, slowMaxMS, delayDistribution
+	t.Cleanup(func() { slowMinMS, slowMaxMS, delayDistribution = prevMin, prevMax, prevDist })
+	slowMinMS, slowMaxMS = 100, 400
+
+	// 분포별로 기대하는 평균 범위 (범위 폭 300ms, 표본 5000개 기준으로 넉넉하게 잡음)
+	tests := []struct {
+		dist             string
+		minMean, maxMean float64
+	}{
+		{"uniform", 240, 260},     // 평균 250
+		{"normal", 240, 260},      // 평균 250, 표준편차 50
+		{"exponential", 190, 210}, // 평균 100 + 300/3, 최댓값에서 잘리므로 조금 작다
+	}
+	for _, tt := range tests {
+		t.Run(tt.dist, func(t *testing.T) {
+			delayDistribution = tt.dist
+			const n = 5000
+			var sum float64
+			for i := 0; i < n; i++ {
+				d := slowDelay()
+				if d < slowMinMS || d > slowMaxMS {
+					t.Fatalf("지연 시간 %dms가 범위 %d~%dms를 벗어났습니다", d, slowMinMS, slowMaxMS)
+				}
+				sum += float64(d)
+			}
+			if mean := sum / n; mean < tt.minMean || mean > tt.maxMean {
+				t.Errorf("평균 %.1fms, want %v~%vms", mean, tt.minMean, tt.maxMean)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 마지막으로 export에 성공한 시각과 첫 성공 여부를 기록하는 SpanExporter 래퍼
+type trackingExporter struct {
+	sdktrace.SpanExporter
+	lastSuccess atomic.Int64 // UnixNano
+	exported    atomic.Bool  // 한 번이라도 export에 성공했는지
+}
+
+func newTrackingExporter(exporter sdktrace.SpanExporter) *trackingExporter {
+	e := &trackingExporter{SpanExporter: exporter}
+	// 시작 직후에는 아직 export가 없으므로 시작 시각을 기준으로 삼는다
+	e.lastSuccess.Store(time.Now().UnixNano())
+	return e
+}
+
+func (e *trackingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err == nil {
+		e.lastSuccess.Store(time.Now().UnixNano())
+		e.exported.Store(true)
+	}
+	return err
+}
+
+// 마지막 export 성공 이후 경과 시간
+func (e *trackingExporter) sinceLastSuccess() time.Duration {
+	return time.Since(time.Unix(0, e.lastSuccess.Load()))
+}
+
+// 한 번이라도 export에 성공했는지
+func (e *trackingExporter) hasExported() bool {
+	return e.exported.Load()
+}
+
+// EXPORT_STALENESS 환경 변수 파싱 (기본값 0 = 검사하지 않음)
+func getExportStaleness() time.Duration {
+	return config.NonNegativeDuration("EXPORT_STALENESS", 0)
+}
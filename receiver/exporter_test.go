@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// ExportSpans가 항상 err를 반환하는 테스트용 exporter
+type failingExporter struct {
+	sdktrace.SpanExporter
+	err error
+}
+
+func (e failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return e.err }
+
+func TestTrackingExporterCountsOnlySuccess(t *testing.T) {
+	ctx := context.Background()
+
+	failing := newTrackingExporter(failingExporter{SpanExporter: tracetest.NewInMemoryExporter(), err: errors.New("연결 실패")})
+	if err := failing.ExportSpans(ctx, nil); err == nil {
+		t.Fatal("감싼 exporter의 오류가 전달되지 않았습니다")
+	}
+	if failing.hasExported() {
+		t.Error("export에 실패했는데 hasExported()가 true입니다")
+	}
+
+	ok := newTrackingExporter(tracetest.NewInMemoryExporter())
+	if ok.hasExported() {
+		t.Error("export 전인데 hasExported()가 true입니다")
+	}
+	if err := ok.ExportSpans(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !ok.hasExported() {
+		t.Error("export에 성공했는데 hasExported()가 false입니다")
+	}
+}
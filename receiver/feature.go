@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+
+	"observability-playground/internal/service"
+)
+
+// baggage에서 기능 플래그를 찾을 때 쓰는 접두사 (sender의 FEATURE_FLAGS)
+const featureFlagPrefix = "feature."
+
+// baggage로 전달된 기능 플래그에 따라 동작을 바꾸는 핸들러
+//
+// 지원하는 플래그:
+//   - new-greeting=on: 다른 응답 문구를 사용
+//   - extra-latency=on: 200ms 지연을 추가해 trace에서 느린 구간으로 보이게 함
+//
+// 받은 플래그는 모두 feature.<이름> 속성으로, 켜진 플래그 목록은 feature.active로 span에 기록된다
+func featureHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "feature-handler")
+	defer span.End()
+
+	flags := make(map[string]string)
+	for _, member := range baggage.FromContext(ctx).Members() {
+		if name, ok := strings.CutPrefix(member.Key(), featureFlagPrefix); ok {
+			flags[name] = member.Value()
+			span.SetAttributes(attribute.String(member.Key(), member.Value()))
+		}
+	}
+
+	var active []string
+	for name, value := range flags {
+		if value == "on" || value == "true" {
+			active = append(active, name)
+		}
+	}
+	sort.Strings(active)
+	span.SetAttributes(attribute.StringSlice("feature.active", active))
+
+	service.LogWithTrace(r.Context(), "기능 플래그 요청", "method", r.Method, "path", r.URL.Path, "active", active)
+
+	isOn := func(name string) bool { v := flags[name]; return v == "on" || v == "true" }
+
+	if isOn("extra-latency") {
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	if isOn("new-greeting") {
+		fmt.Fprintf(w, "수신 서버: 새로운 인사말입니다! (활성 플래그: %s)\n", strings.Join(active, ","))
+		return
+	}
+	fmt.Fprintf(w, "수신 서버: 기본 동작 (활성 플래그: %s)\n", strings.Join(active, ","))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/echo"
+	"observability-playground/internal/service"
+)
+
+// Echo RPC를 제공하는 gRPC 서버를 시작
+// GRPC_PORT: 수신 포트 (기본값 9091, 0이면 gRPC 서버를 띄우지 않음)
+// HTTP와 같은 요청을 gRPC로 보냈을 때의 trace 모양을 비교하기 위한 용도
+func startGRPCServer() (*grpc.Server, error) {
+	port := config.NonNegativeInt("GRPC_PORT", 9091)
+	if port == 0 {
+		return nil, nil
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("gRPC 포트 %d 열기 실패: %w", port, err)
+	}
+
+	srv := newGRPCServer()
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			log.Printf("gRPC 서버 종료: %v", err)
+		}
+	}()
+	log.Printf("gRPC 서버가 포트 %d에서 시작됩니다...", port)
+	return srv, nil
+}
+
+// otelgrpc로 계측하고 Echo 서비스를 등록한 gRPC 서버
+func newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	echo.RegisterServer(srv, echoRPC)
+	return srv
+}
+
+// 받은 메시지를 그대로 돌려주는 Echo RPC
+func echoRPC(ctx context.Context, message string) (string, error) {
+	_, span := tracer.Start(ctx, "echo-rpc")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("echo.message_length", len(message)))
+	service.LogWithTrace(ctx, "gRPC 에코 요청", "message", message)
+	return message, nil
+}
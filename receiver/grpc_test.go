@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"observability-playground/internal/echo"
+)
+
+func TestEchoRPCServerSpanIsChildOfClientSpan(t *testing.T) {
+	h := newHarness(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := newGRPCServer()
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	// sender의 initEchoClient와 같은 계측을 쓰는 클라이언트
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if reply, err := echo.Call(context.Background(), conn, "hello"); err != nil || reply != "hello" {
+		t.Fatalf("echo.Call = %q, %v, want hello", reply, err)
+	}
+	// 서버 span이 끝날 때까지 기다린다
+	srv.GracefulStop()
+
+	var client, server tracetest.SpanStub
+	for _, s := range h.spans() {
+		switch s.SpanKind {
+		case trace.SpanKindClient:
+			client = s
+		case trace.SpanKindServer:
+			server = s
+		}
+	}
+	if !client.SpanContext.IsValid() || !server.SpanContext.IsValid() {
+		t.Fatalf("클라이언트/서버 gRPC span이 없습니다 (기록된 span %d개)", len(h.spans()))
+	}
+	if server.Parent.SpanID() != client.SpanContext.SpanID() || server.SpanContext.TraceID() != client.SpanContext.TraceID() {
+		t.Errorf("서버 span의 부모 = %s, want 클라이언트 span %s", server.Parent.SpanID(), client.SpanContext.SpanID())
+	}
+	if handler := h.span(t, "echo-rpc"); handler.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("echo-rpc의 부모 = %s, want 서버 span %s", handler.Parent.SpanID(), server.SpanContext.SpanID())
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/telemetry"
+)
+
+// receiver를 프로세스 안에서 띄우는 테스트 하니스
+// main과 같은 newMux, initTracer, initHandlers로 httptest.Server를 만들고, 끝난 span을 메모리 exporter에 모은다
+//
+// 사용법:
+//
+//	t.Setenv("ERROR_RATE", "1") // 필요한 환경 변수는 newHarness 전에 설정
+//	h := newHarness(t)
+//	resp := h.get(t, "/error")
+//	span := h.span(t, "error-handler")
+//
+// 외부로는 아무것도 내보내지 않으며(OTEL_TRACES_EXPORTER=none), 테스트가 끝나면 서버와 provider를 정리한다.
+// tracer 등 전역 상태를 바꾸므로 하니스를 쓰는 테스트는 t.Parallel을 쓰지 않는다.
+type harness struct {
+	server   *httptest.Server
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	// /traces를 확인하는 테스트처럼 미리 설정하지 않았으면 span 기록기는 끈다
+	if _, ok := os.LookupEnv("TRACE_RECORDER_SIZE"); !ok {
+		t.Setenv("TRACE_RECORDER_SIZE", "0")
+	}
+
+	cfg := config.Config{ServiceName: "monitoring-test-receiver", Port: 8081, SampleRatio: 1}
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := initTracer(context.Background(), cfg,
+		telemetry.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)),
+	)
+	if err != nil {
+		t.Fatalf("initTracer: %v", err)
+	}
+	closeHandlers, err := initHandlers(cfg)
+	if err != nil {
+		t.Fatalf("initHandlers: %v", err)
+	}
+
+	server := httptest.NewServer(newMux(cfg))
+	t.Cleanup(func() {
+		server.Close()
+		closeHandlers()
+		tp.Shutdown(context.Background())
+	})
+	return &harness{server: server, exporter: exporter, provider: tp}
+}
+
+// path로 GET 요청을 보내고 본문까지 읽은 응답을 반환 (본문은 body로)
+func (h *harness) get(t *testing.T, path string) (*http.Response, string) {
+	t.Helper()
+	resp, err := h.server.Client().Get(h.server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s 본문 읽기: %v", path, err)
+	}
+	return resp, string(body)
+}
+
+// 지금까지 끝난 span 목록
+func (h *harness) spans() tracetest.SpanStubs {
+	return h.exporter.GetSpans()
+}
+
+// 이름이 name인 span (없으면 테스트 실패)
+func (h *harness) span(t *testing.T, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range h.spans() {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("%q span이 없습니다 (기록된 span %d개)", name, len(h.spans()))
+	return tracetest.SpanStub{}
+}
+
+// span의 속성 값 (없으면 false)
+func spanAttr(s tracetest.SpanStub, key string) (any, bool) {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value.AsInterface(), true
+		}
+	}
+	return nil, false
+}
+
+func TestHarnessRecordsServerAndHandlerSpans(t *testing.T) {
+	h := newHarness(t)
+
+	resp, body := h.get(t, "/")
+	if resp.StatusCode != http.StatusOK || body == "" {
+		t.Fatalf("GET / = %d %q", resp.StatusCode, body)
+	}
+
+	server := h.span(t, "home")
+	handler := h.span(t, "home-handler")
+	if handler.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("home-handler의 부모 = %s, want 서버 span %s", handler.Parent.SpanID(), server.SpanContext.SpanID())
+	}
+	if got, _ := spanAttr(server, "http.route"); got != "/" {
+		t.Errorf("서버 span http.route = %v, want /", got)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/service"
+)
+
+// /inject 요청 본문
+type injectRequest struct {
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// JSON 값을 span 속성으로 변환 (문자열, 불리언, 숫자만 허용)
+func injectAttribute(key string, v any) (attribute.KeyValue, error) {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(key, v), nil
+	case bool:
+		return attribute.Bool(key, v), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return attribute.Int64(key, n), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return attribute.KeyValue{}, fmt.Errorf("속성 %q: 잘못된 숫자 %s", key, v)
+		}
+		return attribute.Float64(key, f), nil
+	default:
+		return attribute.KeyValue{}, fmt.Errorf("속성 %q: 지원하지 않는 값 형식 %T (문자열, 불리언, 숫자만 가능)", key, v)
+	}
+}
+
+// 요청한 이름과 속성으로 새 trace의 span을 만들고 trace ID를 돌려주는 핸들러 (POST만 허용)
+// 예: curl -X POST -d '{"name":"demo","attributes":{"user.id":42,"vip":true}}' localhost:8081/inject
+func injectHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req injectRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("잘못된 JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name이 필요합니다.", http.StatusBadRequest)
+		return
+	}
+
+	// 응답이 매번 같도록 키 순서대로 변환
+	keys := make([]string, 0, len(req.Attributes))
+	for k := range req.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		kv, err := injectAttribute(k, req.Attributes[k])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		attrs = append(attrs, kv)
+	}
+
+	// /inject 요청의 trace와 섞이지 않도록 새 trace로 시작하고, 링크로 연결만 해 둔다
+	_, span := tracer.Start(r.Context(), req.Name,
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(r.Context())),
+		trace.WithAttributes(attrs...),
+	)
+	span.End()
+
+	sc := span.SpanContext()
+	service.LogWithTrace(r.Context(), "사용자 정의 span 생성", "name", req.Name, "injected_trace_id", sc.TraceID().String())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+		"sampled":  sc.IsSampled(),
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectCreatesSpanWithAttributes(t *testing.T) {
+	h := newHarness(t)
+
+	body := `{"name":"demo","attributes":{"user.id":42,"ratio":0.5,"vip":true,"plan":"pro"}}`
+	resp, err := h.server.Client().Post(h.server.URL+"/inject", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /inject = %d, want 200", resp.StatusCode)
+	}
+	var got struct {
+		TraceID string `json:"trace_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("JSON 파싱 실패: %v", err)
+	}
+	traceID, err := trace.TraceIDFromHex(got.TraceID)
+	if err != nil || !traceID.IsValid() {
+		t.Fatalf("trace_id %q가 유효하지 않습니다", got.TraceID)
+	}
+
+	span := h.span(t, "demo")
+	if span.SpanContext.TraceID() != traceID {
+		t.Errorf("demo span trace ID = %s, want 응답의 %s", span.SpanContext.TraceID(), traceID)
+	}
+	if span.Parent.IsValid() {
+		t.Error("demo span이 새 trace의 루트가 아닙니다")
+	}
+	want := map[string]any{"user.id": int64(42), "ratio": 0.5, "vip": true, "plan": "pro"}
+	for key, v := range want {
+		if got, _ := spanAttr(span, key); got != v {
+			t.Errorf("속성 %s = %v (%T), want %v (%T)", key, got, got, v, v)
+		}
+	}
+}
+
+func TestInjectRejectsInvalidRequests(t *testing.T) {
+	h := newHarness(t)
+
+	tests := []struct {
+		name, body string
+	}{
+		{"이름 없음", `{"attributes":{"a":1}}`},
+		{"객체 값", `{"name":"demo","attributes":{"a":{"b":1}}}`},
+		{"배열 값", `{"name":"demo","attributes":{"a":[1,2]}}`},
+		{"null 값", `{"name":"demo","attributes":{"a":null}}`},
+		{"잘못된 JSON", `{"name":`},
+	}
+	for _, tt := range tests {
+		resp, err := h.server.Client().Post(h.server.URL+"/inject", "application/json", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: POST /inject = %d, want 400", tt.name, resp.StatusCode)
+		}
+	}
+
+	if resp, _ := h.get(t, "/inject"); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /inject = %d, want 405", resp.StatusCode)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 초당 요청 수를 집계하는 구간 수 (초 단위 이동 평균)
+const loadWindowSeconds = 10
+
+// 최근 요청률을 추적하고 부하 수준(low/medium/high)을 판정
+type loadTracker struct {
+	current atomic.Int64 // 현재 1초 구간의 요청 수
+
+	mu      sync.Mutex
+	buckets [loadWindowSeconds]int64
+	next    int
+
+	rate       atomic.Uint64 // 최근 구간의 초당 요청 수 x 1000 (원자적으로 저장하기 위해 정수 사용)
+	mediumRPS  float64
+	highRPS    float64
+	stopTicker func()
+}
+
+var requestLoad *loadTracker
+
+// LOAD_MEDIUM_RPS, LOAD_HIGH_RPS 환경 변수로 임계값을 읽어 추적기를 시작
+// 기본값은 medium 5 RPS, high 20 RPS
+func startLoadTracker() *loadTracker {
+	t := &loadTracker{
+		mediumRPS: config.NonNegativeFloat("LOAD_MEDIUM_RPS", 5),
+		highRPS:   config.NonNegativeFloat("LOAD_HIGH_RPS", 20),
+	}
+	if t.highRPS < t.mediumRPS {
+		log.Printf("LOAD_HIGH_RPS(%v)가 LOAD_MEDIUM_RPS(%v)보다 작아 기본값을 사용합니다.", t.highRPS, t.mediumRPS)
+		t.mediumRPS, t.highRPS = 5, 20
+	}
+
+	ticker := time.NewTicker(time.Second)
+	done := make(chan struct{})
+	t.stopTicker = func() {
+		ticker.Stop()
+		close(done)
+	}
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				t.roll()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	log.Printf("부하 수준 임계값: medium >= %v RPS, high >= %v RPS", t.mediumRPS, t.highRPS)
+	return t
+}
+
+// 1초 구간을 마감하고 이동 평균 요청률을 갱신
+func (t *loadTracker) roll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buckets[t.next] = t.current.Swap(0)
+	t.next = (t.next + 1) % loadWindowSeconds
+
+	var total int64
+	for _, n := range t.buckets {
+		total += n
+	}
+	t.rate.Store(uint64(total * 1000 / loadWindowSeconds))
+}
+
+// 최근 초당 요청 수
+func (t *loadTracker) requestRate() float64 {
+	return float64(t.rate.Load()) / 1000
+}
+
+// 현재 부하 수준
+func (t *loadTracker) level() string {
+	rate := t.requestRate()
+	switch {
+	case rate >= t.highRPS:
+		return "high"
+	case rate >= t.mediumRPS:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// 요청 수를 세는 미들웨어
+func withRequestCounter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestLoad != nil {
+			requestLoad.current.Add(1)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 모든 span 시작 시 load.level 속성을 붙이는 SpanProcessor
+type loadLevelProcessor struct{}
+
+func (loadLevelProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	if requestLoad == nil {
+		return
+	}
+	s.SetAttributes(
+		attribute.String("load.level", requestLoad.level()),
+		attribute.Float64("load.rps", requestLoad.requestRate()),
+	)
+}
+
+func (loadLevelProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (loadLevelProcessor) Shutdown(context.Context) error   { return nil }
+func (loadLevelProcessor) ForceFlush(context.Context) error { return nil }
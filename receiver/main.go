@@ -1,108 +1,237 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"context"
 	"math/rand"
 
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
 	"go.opentelemetry.io/otel/trace"
+
+	"flag"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+	"observability-playground/internal/stats"
+	"observability-playground/internal/telemetry"
 )
 
 var tracer trace.Tracer
 
-func initTracer() (*sdktrace.TracerProvider, error) {
-	// OTLP exporter 생성
-	ctx := context.Background()
+// export 성공 시각을 추적하는 exporter (상태 확인에 사용)
+var spanExporter *trackingExporter
 
-	// Tempo 서버로 전송
-	tempoEndpoint := os.Getenv("TEMPO_ENDPOINT")
-	if tempoEndpoint == "" {
-		tempoEndpoint = "tempo:4317" // 기본값
-	}
+// 마지막 export 성공 후 이 시간이 지나면 비정상으로 간주 (0이면 검사하지 않음)
+var exportStaleness time.Duration
 
-	client := otlptracegrpc.NewClient(
-		otlptracegrpc.WithEndpoint(tempoEndpoint),
-		otlptracegrpc.WithInsecure(), // 테스트 환경에서는 TLS 없이 설정
-	)
-	exporter, err := otlptrace.New(ctx, client)
+// errorHandler가 500을 반환할 확률 (ERROR_RATE, 기본값 0.2)
+var errorRate float64
+
+// slowResponseHandler의 지연 범위 (SLOW_MIN_MS~SLOW_MAX_MS, 기본값 100~2000ms)
+var slowMinMS, slowMaxMS int
+
+func main() {
+	// 명령행 플래그 파싱 (지정한 플래그가 환경 변수보다 우선)
+	// 공통 설정 읽기 (OTEL_SERVICE_NAME, PORT, TEMPO_ENDPOINT, 샘플링 비율)
+	// 우선순위는 명령행 플래그 > 환경 변수 > 기본값
+	// 잘못된 값이 있으면 기본값으로 대신하지 않고 시작을 멈춘다
+	cfg, _, err := config.Load(config.Config{
+		ServiceName: "monitoring-test-receiver",
+		Port:        8081, // sender와 다른 포트 사용
+		SampleRatio: 1.0,
+	}, os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return // -h: 사용법만 출력하고 끝낸다
+	}
 	if err != nil {
-		return nil, fmt.Errorf("OTLP exporter 생성 실패: %w", err)
+		log.Fatalf("%v", err)
 	}
+	serviceName := cfg.ServiceName
 
-	// 리소스 설정 (서비스 이름 등)
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String("monitoring-test-receiver"),
-			attribute.String("environment", "dev"),
-		),
-	)
+	// OTLP 로그 전송 준비 (OTEL_LOGS_ENDPOINT가 있을 때만)
+	lp, err := telemetry.InitLogger(context.Background(), serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("리소스 생성 실패: %w", err)
+		log.Fatalf("로거 provider 초기화 실패: %v", err)
+	}
+	if lp != nil {
+		defer telemetry.Shutdown("logger provider", lp.Shutdown)
 	}
 
-	// TracerProvider 설정
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(res),
-	)
-	otel.SetTracerProvider(tp)
-
-	// 글로벌 tracer 설정
-	tracer = tp.Tracer("monitoring-test-receiver")
+	// 로거 초기화 (LOG_SAMPLE_N, OTLP 로그 전송 여부)
+	service.InitLogger(serviceName, lp != nil)
 
-	return tp, nil
-}
+	// SDK 내부 오류 처리기 등록
+	service.InitErrorHandler(serviceName)
 
-func main() {
-	// 트레이서 초기화
-	tp, err := initTracer()
+	// 트레이서 초기화 (export 성공 시각 추적과 span 속성 보강 포함)
+	tp, err := initTracer(context.Background(), cfg)
 	if err != nil {
 		log.Fatalf("트레이서 초기화 실패: %v", err)
 	}
-	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
-			log.Printf("Error shutting down tracer provider: %v", err)
-		}
-	}()
+	defer telemetry.Shutdown("tracer provider", tp.Shutdown)
+
+	// 미터 초기화
+	mp, err := telemetry.InitMeter(context.Background(), serviceName)
+	if err != nil {
+		log.Fatalf("미터 초기화 실패: %v", err)
+	}
+	defer telemetry.Shutdown("meter provider", mp.Shutdown)
+	service.InitRequestMetrics(serviceName)
+
+	closeHandlers, err := initHandlers(cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer closeHandlers()
 
-	// 핸들러를 OpenTelemetry로 감싸기
-	http.Handle("/", otelhttp.NewHandler(http.HandlerFunc(homeHandler), "home"))
-	http.Handle("/health", otelhttp.NewHandler(http.HandlerFunc(healthHandler), "health"))
-	http.Handle("/slow", otelhttp.NewHandler(http.HandlerFunc(slowResponseHandler), "slow"))
-	http.Handle("/error", otelhttp.NewHandler(http.HandlerFunc(errorHandler), "error"))
+	// 핸들러를 공통 미들웨어와 OpenTelemetry로 감싸기
+	mux := newMux(cfg)
+
+	// gRPC 서버 시작 (HTTP 서버가 종료되면 진행 중인 RPC를 마무리하고 멈춘다)
+	grpcSrv, err := startGRPCServer()
+	if err != nil {
+		log.Fatalf("gRPC 서버 시작 실패: %v", err)
+	}
+	if grpcSrv != nil {
+		defer grpcSrv.GracefulStop()
+	}
 
 	// 서버 시작
-	port := 8081 // sender와 다른 포트 사용
+	port := cfg.Port
 	log.Printf("수신 서버가 포트 %d에서 시작됩니다...", port)
-	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil); err != nil {
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+	if err := service.RunServer(srv); err != nil {
 		log.Fatalf("수신 서버 시작 실패: %v", err)
 	}
 }
 
+// receiver의 TracerProvider를 만들고 전역 tracer를 설정한다
+// export 성공 시각 추적(/ready)과 span 속성 보강 processor를 붙이며, 테스트는 extra로 processor를 더한다
+func initTracer(ctx context.Context, cfg config.Config, extra ...telemetry.Option) (*sdktrace.TracerProvider, error) {
+	options := append([]telemetry.Option{
+		telemetry.WithExporterWrapper(func(exporter sdktrace.SpanExporter) sdktrace.SpanExporter {
+			spanExporter = newTrackingExporter(exporter)
+			return spanExporter
+		}),
+		telemetry.WithSpanProcessor(loadLevelProcessor{}),
+		telemetry.WithSpanProcessor(newBaggageAttributeProcessor()),
+	}, extra...)
+	tp, err := telemetry.InitTracer(ctx, cfg, options...)
+	if err != nil {
+		return nil, err
+	}
+	tracer = tp.Tracer(cfg.ServiceName)
+	return tp, nil
+}
+
+// 핸들러가 쓰는 설정과 자원(카운터 DB, 부하 추적기 등)을 환경 변수로 초기화한다
+// 핸들러가 만드는 메트릭은 cfg.ServiceName 이름의 meter에 기록하며, 반환한 함수로 자원을 정리한다
+func initHandlers(cfg config.Config) (func(), error) {
+	exportStaleness = getExportStaleness()
+	errorRate = getErrorRate()
+	slowMinMS, slowMaxMS = getSlowRange()
+	delayDistribution = getDelayDistribution()
+	homeMessage = defaultHomeMessage
+	if v := os.Getenv("HOME_MESSAGE"); v != "" {
+		homeMessage = v
+	}
+	routeTimeout = getRouteTimeout()
+	// otelhttp transport는 만들 때의 TracerProvider를 쓰므로 initTracer 뒤에 만든다
+	downstreamClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	initCPUBurnMax()
+	initCache()
+	initConcurrencyLimit(cfg.ServiceName)
+	initRateLimit()
+	if err := initCounterDB(); err != nil {
+		return nil, err
+	}
+	requestLoad = startLoadTracker()
+	return func() {
+		requestLoad.stopTicker()
+		counterDB.Close()
+	}, nil
+}
+
+// receiver의 모든 HTTP 핸들러를 등록한 mux를 만든다
+// main과 테스트 하니스가 같은 라우팅을 쓰도록 분리해 두었다
+// 데모 트래픽을 받는 경로만 withCounterIncrement로 감싸 /count에 반영하고, 상태 확인과 관리용 경로는 세지 않는다
+func newMux(cfg config.Config) *service.InstrumentedMux {
+	mux := service.NewInstrumentedMux(cfg.ServiceName)
+	handle(mux, "/", "home", withCounterIncrement(homeHandler))
+	handle(mux, "/health", "health", healthHandler)
+	handle(mux, "/ready", "ready", readyHandler)
+	handle(mux, "/slow", "slow", withRateLimit(withCounterIncrement(slowResponseHandler)))
+	handle(mux, "/error", "error", withRateLimit(withCounterIncrement(errorHandler)))
+	handle(mux, "/panic", "panic", panicHandler)
+	handle(mux, "/echo", "echo", withCounterIncrement(echoHandler))
+	handle(mux, "/cascade", "cascade", withCounterIncrement(cascadeHandler(cfg.Port)))
+	handle(mux, "/cascade/downstream", "cascade-downstream", cascadeDownstreamHandler)
+	handle(mux, "/cpu", "cpu", withCounterIncrement(cpuBurnHandler))
+	handle(mux, "/feature", "feature", withCounterIncrement(featureHandler))
+	handle(mux, "/cached", "cached", withCounterIncrement(cachedHandler))
+	handle(mux, "/count", "count", countHandler)
+	handle(mux, "/waterfall", "waterfall", withCounterIncrement(waterfallHandler))
+	handle(mux, "/version", "version", service.VersionHandler)
+	// 수집 요청까지 trace로 남지 않도록 미들웨어 없이 등록
+	mux.Handle("/metrics", telemetry.MetricsHandler())
+	mux.Handle("/stats", stats.Handler())
+	// 호스트 이름, 프로세스 정보 등 내부 정보가 드러나므로 DEBUG_ENDPOINTS=true일 때만 노출
+	if service.DebugEndpointsEnabled() {
+		mux.Handle("/traces", telemetry.TracesHandler())
+		handle(mux, "/resource", "resource", service.ResourceHandler)
+		handle(mux, "/admin/shutdown", "admin-shutdown", service.AdminShutdownHandler)
+	}
+	handle(mux, "/trace", "trace", traceHandler)
+	handle(mux, "/inject", "inject", injectHandler)
+	return mux
+}
+
+// 공통 미들웨어를 목록의 위쪽부터 바깥에 오도록 적용하고 OpenTelemetry로 감싸 핸들러를 등록
+func handle(mux *service.InstrumentedMux, pattern, operation string, h http.HandlerFunc) {
+	mux.HandleTraced(pattern, operation, service.Chain(h,
+		stats.Middleware(pattern),
+		service.WithMetrics(pattern),
+		service.WithRoute(pattern),
+		service.WithTraceIDHeader,
+		service.WithRequestID,
+		telemetry.WithBaggageTrimmed,
+		service.WithRequestAttributes,
+		service.WithStatusClass,
+		service.WithRecovery,
+		withTraceSource,
+		withRequestCounter,
+		withConcurrencyLimit(pattern),
+		withRouteTimeout,
+	))
+}
+
+// 홈페이지 응답 메시지 (HOME_MESSAGE, 기본값 "Hello, World!")
+// 인스턴스마다 다르게 주면 데모에서 어느 인스턴스가 응답했는지 바로 구분할 수 있다
+const defaultHomeMessage = "Hello, World!"
+
+var homeMessage = defaultHomeMessage
+
 // 기본 홈페이지 핸들러
 func homeHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_, span := tracer.Start(ctx, "home-handler")
 	defer span.End()
 
-	log.Printf("수신: 홈페이지 요청: %s %s", r.Method, r.URL.Path)
+	service.LogWithTrace(ctx, "수신: 홈페이지 요청", "method", r.Method, "path", r.URL.Path)
 	span.SetAttributes(attribute.String("http.method", r.Method))
 
-	fmt.Fprintf(w, "수신 서버: Hello, World!\n")
+	fmt.Fprintf(w, "수신 서버: %s\n", homeMessage)
 }
 
 // 상태 확인 핸들러
@@ -111,49 +240,195 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	_, span := tracer.Start(ctx, "health-handler")
 	defer span.End()
 
-	log.Printf("수신: 상태 확인 요청: %s %s", r.Method, r.URL.Path)
+	service.LogWithTrace(ctx, "수신: 상태 확인 요청", "method", r.Method, "path", r.URL.Path)
+
+	// 일정 시간 동안 export가 성공하지 못했다면 파이프라인 이상으로 판단
+	if exportStaleness > 0 {
+		since := spanExporter.sinceLastSuccess()
+		span.SetAttributes(attribute.Int64("export.since_last_success_ms", since.Milliseconds()))
+		if since > exportStaleness {
+			slog.WarnContext(ctx, "마지막 export 성공 후 허용 시간 초과", "since", since, "allowed", exportStaleness)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "수신 서버: 상태: 비정상 (마지막 export 성공 후 %v 경과)\n", since.Round(time.Second))
+			return
+		}
+	}
+
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, "수신 서버: 상태: 정상\n")
 }
 
+// 준비 상태 확인 핸들러 (readiness probe용)
+// trace 파이프라인이 한 번이라도 export에 성공하기 전까지는 503을 반환한다
+// 이 요청 자체도 span을 만들기 때문에 트래픽이 없어도 probe만으로 첫 export가 일어난다
+func readyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "ready-handler")
+	defer span.End()
+
+	ready := spanExporter.hasExported()
+	span.SetAttributes(attribute.Bool("export.ready", ready))
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "수신 서버: 준비 안 됨 (아직 export에 성공하지 못함)\n")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "수신 서버: 준비 완료\n")
+}
+
 // 느린 응답을 생성하는 핸들러
 func slowResponseHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	_, span := tracer.Start(ctx, "slow-handler")
 	defer span.End()
 
-	log.Printf("느린 응답 요청: %s %s", r.Method, r.URL.Path)
+	service.LogWithTrace(ctx, "느린 응답 요청", "method", r.Method, "path", r.URL.Path)
 
-	// 0.1초에서 2초 사이의 무작위 지연
-	delay := 100 + rand.Intn(1900)
-	span.SetAttributes(attribute.Int("delay_ms", delay))
+	// slowMinMS에서 slowMaxMS 사이의 무작위 지연 (DELAY_DISTRIBUTION에 따른 분포)
+	delay := slowDelay()
+	span.SetAttributes(
+		attribute.Int("delay_ms", delay),
+		attribute.String("delay.distribution", delayDistribution),
+	)
 
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	// context가 취소되면 대기를 중단 (요청 제한 시간 등)
+	select {
+	case <-time.After(time.Duration(delay) * time.Millisecond):
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "느린 응답 취소됨", "error", ctx.Err())
+		span.SetAttributes(attribute.Bool("cancelled", true))
+		span.RecordError(ctx.Err())
+		return
+	}
 
 	fmt.Fprintf(w, "느린 응답 완료! 지연 시간: %d ms\n", delay)
 }
 
+// SLOW_MIN_MS, SLOW_MAX_MS 환경 변수 파싱
+// 둘 다 양수이고 최소값이 최대값 이하일 때만 적용하고, 아니면 기본 범위 100~2000ms를 사용
+func getSlowRange() (int, int) {
+	const defaultMin, defaultMax = 100, 2000
+
+	minMS := config.PositiveInt("SLOW_MIN_MS", defaultMin)
+	maxMS := config.PositiveInt("SLOW_MAX_MS", defaultMax)
+	if minMS > maxMS {
+		log.Printf("SLOW_MIN_MS(%d)가 SLOW_MAX_MS(%d)보다 큽니다. 기본 범위 %d~%dms를 사용합니다.", minMS, maxMS, defaultMin, defaultMax)
+		return defaultMin, defaultMax
+	}
+	return minMS, maxMS
+}
+
 // 에러를 발생시키는 핸들러
 func errorHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	_, span := tracer.Start(ctx, "error-handler")
+
+	// errorRate 확률로 500 에러 반환
+	// 에러 여부를 span 시작 전에 정해 error 속성을 넘기므로 비율 샘플링 중에도 에러 span은 항상 남는다
+	fail := rand.Float64() < errorRate
+	var opts []trace.SpanStartOption
+	if fail {
+		opts = append(opts, trace.WithAttributes(attribute.String("error", "true")))
+	}
+	_, span := tracer.Start(ctx, "error-handler", opts...)
 	defer span.End()
 
-	log.Printf("에러 발생 요청: %s %s", r.Method, r.URL.Path)
+	service.LogWithTrace(ctx, "에러 발생 요청", "method", r.Method, "path", r.URL.Path)
 
-	// 20% 확률로 500 에러 반환
-	if rand.Intn(5) == 0 {
-		log.Printf("500 에러 발생")
-		span.SetAttributes(attribute.String("error", "true"))
+	span.SetAttributes(attribute.Float64("error.rate", errorRate))
+	if fail {
+		err := errors.New("의도적으로 발생시킨 500 에러")
+		slog.ErrorContext(ctx, "500 에러 발생")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "내부 서버 오류가 발생했습니다!\n")
 		return
 	}
 
-	// 80% 확률로 정상 응답
+	// 나머지 경우 정상 응답
 	fmt.Fprintf(w, "이번에는 에러가 발생하지 않았습니다!\n")
 }
 
+// ERROR_RATE 환경 변수 파싱 (0.0~1.0, 없거나 잘못된 값이면 0.2)
+func getErrorRate() float64 {
+	const defaultRate = 0.2
+
+	v := os.Getenv("ERROR_RATE")
+	if v == "" {
+		return defaultRate
+	}
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		log.Printf("잘못된 ERROR_RATE 값 %q, 기본값 %v를 사용합니다.", v, defaultRate)
+		return defaultRate
+	}
+	log.Printf("에러 발생 확률: %v", rate)
+	return rate
+}
+
+// 일부러 panic을 일으키는 핸들러 (복구 미들웨어 확인용)
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "panic-handler")
+	defer span.End()
+
+	service.LogWithTrace(ctx, "panic 요청", "method", r.Method, "path", r.URL.Path)
+	panic("의도적으로 발생시킨 panic")
+}
+
+// 수신한 요청 정보를 그대로 돌려주는 핸들러 (전파 진단용)
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "echo-handler")
+	defer span.End()
+
+	service.LogWithTrace(ctx, "에코 요청", "method", r.Method, "path", r.URL.Path)
+
+	headers := make(map[string]string, len(r.Header))
+	for k := range r.Header {
+		headers[k] = r.Header.Get(k)
+	}
+
+	traceparent := r.Header.Get("traceparent")
+	span.SetAttributes(attribute.String("echo.traceparent", traceparent))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"traceparent": traceparent,
+		"headers":     headers,
+	})
+}
+
+// 현재 요청의 trace 정보와 span 경과 시간을 JSON으로 돌려주는 진단 핸들러
+func traceHandler(w http.ResponseWriter, r *http.Request) {
+	// otelhttp가 만든 서버 span을 그대로 읽는다
+	span := trace.SpanFromContext(r.Context())
+	sc := span.SpanContext()
+
+	service.LogWithTrace(r.Context(), "trace 정보 요청", "method", r.Method, "path", r.URL.Path)
+
+	info := map[string]any{
+		"trace_id": sc.TraceID().String(),
+		"span_id":  sc.SpanID().String(),
+		"sampled":  sc.IsSampled(),
+	}
+
+	// SDK span이면 이름과 시작 이후 경과 시간도 함께 보고
+	if ro, ok := span.(sdktrace.ReadOnlySpan); ok {
+		elapsed := time.Since(ro.StartTime())
+		info["span_name"] = ro.Name()
+		info["start_time"] = ro.StartTime().Format(time.RFC3339Nano)
+		info["elapsed_ms"] = float64(elapsed.Microseconds()) / 1000
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
 func init() {
 	// 난수 생성기 초기화
 	rand.Seed(time.Now().UnixNano())
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestGetErrorRate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"", 0.2},
+		{"0", 0},
+		{"1", 1},
+		{"0.5", 0.5},
+		{"1.5", 0.2},
+		{"-0.1", 0.2},
+		{"often", 0.2},
+	}
+	for _, tt := range tests {
+		t.Setenv("ERROR_RATE", tt.value)
+		if got := getErrorRate(); got != tt.want {
+			t.Errorf("ERROR_RATE=%q: getErrorRate() = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestErrorHandlerFollowsErrorRate(t *testing.T) {
+	tests := []struct {
+		rate       string
+		wantStatus int
+		wantCode   codes.Code
+	}{
+		{"1", http.StatusInternalServerError, codes.Error},
+		{"0", http.StatusOK, codes.Unset},
+	}
+	for _, tt := range tests {
+		t.Run("ERROR_RATE="+tt.rate, func(t *testing.T) {
+			t.Setenv("ERROR_RATE", tt.rate)
+			h := newHarness(t)
+
+			// 무작위 값과 상관없이 결과가 같아야 하므로 여러 번 요청한다
+			for i := 0; i < 20; i++ {
+				if resp, _ := h.get(t, "/error"); resp.StatusCode != tt.wantStatus {
+					t.Fatalf("%d번째 GET /error = %d, want %d", i+1, resp.StatusCode, tt.wantStatus)
+				}
+			}
+			for _, s := range h.spans() {
+				if s.Name == "error-handler" && s.Status.Code != tt.wantCode {
+					t.Fatalf("error-handler span 상태 = %v, want %v", s.Status.Code, tt.wantCode)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSlowRange(t *testing.T) {
+	tests := []struct {
+		min, max         string
+		wantMin, wantMax int
+	}{
+		{"", "", 100, 2000},
+		{"10", "50", 10, 50},
+		{"30", "30", 30, 30},
+		{"50", "10", 100, 2000}, // min > max
+		{"0", "500", 100, 500},  // 양수가 아닌 값은 그 항목만 기본값
+		{"fast", "", 100, 2000},
+	}
+	for _, tt := range tests {
+		t.Setenv("SLOW_MIN_MS", tt.min)
+		t.Setenv("SLOW_MAX_MS", tt.max)
+		if gotMin, gotMax := getSlowRange(); gotMin != tt.wantMin || gotMax != tt.wantMax {
+			t.Errorf("SLOW_MIN_MS=%q SLOW_MAX_MS=%q: getSlowRange() = %d, %d, want %d, %d",
+				tt.min, tt.max, gotMin, gotMax, tt.wantMin, tt.wantMax)
+		}
+	}
+}
+
+func TestSlowHandlerDelayIsFixedWhenMinEqualsMax(t *testing.T) {
+	t.Setenv("SLOW_MIN_MS", "5")
+	t.Setenv("SLOW_MAX_MS", "5")
+	for _, dist := range []string{"uniform", "normal", "exponential"} {
+		t.Run(dist, func(t *testing.T) {
+			t.Setenv("DELAY_DISTRIBUTION", dist)
+			h := newHarness(t)
+
+			for i := 0; i < 5; i++ {
+				h.get(t, "/slow")
+			}
+			n := 0
+			for _, s := range h.spans() {
+				if s.Name != "slow-handler" {
+					continue
+				}
+				n++
+				if got, _ := spanAttr(s, "delay_ms"); got != int64(5) {
+					t.Errorf("delay_ms = %v, want 5", got)
+				}
+			}
+			if n != 5 {
+				t.Errorf("slow-handler span %d개, want 5", n)
+			}
+		})
+	}
+}
+
+func TestReadyWaitsForFirstExport(t *testing.T) {
+	h := newHarness(t)
+
+	if resp, _ := h.get(t, "/ready"); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("export 전 GET /ready = %d, want 503", resp.StatusCode)
+	}
+	// /health는 export와 상관없는 liveness 확인
+	if resp, _ := h.get(t, "/health"); resp.StatusCode != http.StatusOK {
+		t.Errorf("export 전 GET /health = %d, want 200", resp.StatusCode)
+	}
+
+	// 지금까지 끝난 span을 batch processor가 내보내게 한다
+	if err := h.provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if resp, _ := h.get(t, "/ready"); resp.StatusCode != http.StatusOK {
+		t.Errorf("export 후 GET /ready = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestHomeHandlerUsesHomeMessage(t *testing.T) {
+	tests := []struct {
+		message, want string
+	}{
+		{"", "수신 서버: Hello, World!\n"},
+		{"인스턴스 B", "수신 서버: 인스턴스 B\n"},
+	}
+	for _, tt := range tests {
+		t.Run("HOME_MESSAGE="+tt.message, func(t *testing.T) {
+			t.Setenv("HOME_MESSAGE", tt.message)
+			h := newHarness(t)
+			if _, body := h.get(t, "/"); body != tt.want {
+				t.Errorf("GET / 본문 = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracesEndpointRequiresDebugEndpoints(t *testing.T) {
+	t.Run("DEBUG_ENDPOINTS 없음", func(t *testing.T) {
+		t.Setenv("DEBUG_ENDPOINTS", "")
+		h := newHarness(t)
+		// 등록되지 않았으면 "/" 홈 핸들러가 응답한다
+		if resp, body := h.get(t, "/traces"); resp.Header.Get("Content-Type") == "application/json" {
+			t.Errorf("DEBUG_ENDPOINTS 없이 /traces가 span 목록을 돌려줬습니다: %.80s", body)
+		}
+	})
+
+	t.Run("DEBUG_ENDPOINTS=true", func(t *testing.T) {
+		t.Setenv("DEBUG_ENDPOINTS", "true")
+		t.Setenv("TRACE_RECORDER_SIZE", "10")
+		h := newHarness(t)
+		h.get(t, "/health")
+
+		resp, body := h.get(t, "/traces")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET /traces = %d, want 200", resp.StatusCode)
+		}
+		var spans []struct{ Name string }
+		if err := json.Unmarshal([]byte(body), &spans); err != nil {
+			t.Fatalf("JSON 파싱 실패: %v", err)
+		}
+		var names []string
+		for _, s := range spans {
+			names = append(names, s.Name)
+		}
+		if !slices.Contains(names, "health-handler") || !slices.Contains(names, "health") {
+			t.Errorf("/traces의 span 이름 = %v, want health와 health-handler 포함", names)
+		}
+	})
+}
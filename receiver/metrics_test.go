@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"observability-playground/internal/service"
+	"observability-playground/internal/telemetry"
+)
+
+func TestMetricsEndpointExposesRequestMetrics(t *testing.T) {
+	// Prometheus exporter는 기본 registry에 등록되므로 프로세스에서 한 번만 초기화한다
+	mp, err := telemetry.InitMeter(context.Background(), "monitoring-test-receiver")
+	if err != nil {
+		t.Fatalf("InitMeter: %v", err)
+	}
+	t.Cleanup(func() { mp.Shutdown(context.Background()) })
+	service.InitRequestMetrics("monitoring-test-receiver")
+
+	h := newHarness(t)
+	h.get(t, "/")
+
+	resp, body := h.get(t, "/metrics")
+	if resp.StatusCode != 200 {
+		t.Fatalf("GET /metrics = %d", resp.StatusCode)
+	}
+	for _, name := range []string{"http_server_requests_total", "http_server_request_duration_ms"} {
+		if !strings.Contains(body, name) {
+			t.Errorf("/metrics에 %s가 없습니다", name)
+		}
+	}
+	if !strings.Contains(body, `http_route="/"`) {
+		t.Error(`/metrics에 http_route="/" 레이블이 없습니다`)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+)
+
+// 요청별 제한 시간 (0이면 제한 없음)
+var routeTimeout time.Duration
+
+// ROUTE_TIMEOUT 환경 변수 파싱 (기본값 0 = 제한 없음)
+func getRouteTimeout() time.Duration {
+	return config.NonNegativeDuration("ROUTE_TIMEOUT", 0)
+}
+
+// 응답을 이미 썼는지 기록하는 ResponseWriter
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// 요청 context에 제한 시간을 거는 미들웨어
+// 제한 시간을 넘기면 span에 timeout=true를 기록하고, 핸들러가 응답을 쓰지 않았다면 503을 반환한다
+func withRouteTimeout(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if routeTimeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), routeTimeout)
+		defer cancel()
+
+		tw := &timeoutResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(tw, r.WithContext(ctx))
+
+		if ctx.Err() != context.DeadlineExceeded {
+			return
+		}
+
+		span := trace.SpanFromContext(ctx)
+		span.SetAttributes(attribute.Bool("timeout", true))
+		span.SetStatus(codes.Error, "요청 제한 시간 초과")
+		slog.Warn("요청 제한 시간 초과", "method", r.Method, "path", r.URL.Path, "timeout", routeTimeout)
+
+		if !tw.wroteHeader {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "요청 제한 시간(%v)을 초과했습니다.\n", routeTimeout)
+		}
+	})
+}
+
+// 동시 처리 요청 수를 제한하는 세마포어 (nil이면 제한 없음)
+var concurrencySem chan struct{}
+
+// 슬롯을 얻기까지 기다린 시간 히스토그램
+var queueWaitHistogram metric.Float64Histogram
+
+// MAX_CONCURRENT_REQUESTS 환경 변수로 세마포어 초기화 (기본값 0 = 제한 없음)
+// 대기 시간 히스토그램은 serviceName 이름의 meter에 만든다
+func initConcurrencyLimit(serviceName string) {
+	if n := config.NonNegativeInt("MAX_CONCURRENT_REQUESTS", 0); n > 0 {
+		concurrencySem = make(chan struct{}, n)
+		log.Printf("동시 처리 요청 수를 %d개로 제한합니다.", n)
+	}
+
+	var err error
+	queueWaitHistogram, err = otel.Meter(serviceName).Float64Histogram(
+		"http.server.queue_wait",
+		metric.WithDescription("동시 처리 슬롯을 얻기까지 대기한 시간"),
+		metric.WithUnit("ms"),
+	)
+	if err != nil {
+		log.Printf("대기 시간 히스토그램 생성 실패: %v", err)
+	}
+}
+
+// 동시 처리 슬롯을 얻은 뒤에 핸들러를 실행하는 미들웨어를 만든다
+// 대기 시간을 span 속성과 히스토그램으로 기록한다 (히스토그램의 http.route는 withMetrics처럼 등록한 패턴)
+func withConcurrencyLimit(route string) service.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if concurrencySem == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			span := trace.SpanFromContext(ctx)
+			start := time.Now()
+
+			select {
+			case concurrencySem <- struct{}{}:
+			case <-ctx.Done():
+				slog.Warn("동시 처리 슬롯 대기 중 요청 취소", "method", r.Method, "path", r.URL.Path)
+				span.SetAttributes(attribute.Float64("concurrency.wait_ms", float64(time.Since(start).Microseconds())/1000))
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-concurrencySem }()
+
+			waitMs := float64(time.Since(start).Microseconds()) / 1000
+			span.SetAttributes(attribute.Float64("concurrency.wait_ms", waitMs))
+			if queueWaitHistogram != nil {
+				queueWaitHistogram.Record(ctx, waitMs, metric.WithAttributes(attribute.String("http.route", route)))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
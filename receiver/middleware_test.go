@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+)
+
+func TestRecoveryRecordsPanicOnServerSpan(t *testing.T) {
+	h := newHarness(t)
+
+	resp, _ := h.get(t, "/panic")
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("GET /panic = %d, want 500", resp.StatusCode)
+	}
+	// 서버가 살아 있어 다음 요청도 처리해야 한다
+	if resp, _ := h.get(t, "/health"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("panic 후 GET /health = %d, want 200", resp.StatusCode)
+	}
+
+	span := h.span(t, "panic")
+	if span.Status.Code != codes.Error {
+		t.Errorf("서버 span 상태 = %v, want Error", span.Status.Code)
+	}
+	var recorded bool
+	for _, e := range span.Events {
+		if e.Name != semconv.ExceptionEventName {
+			continue
+		}
+		for _, kv := range e.Attributes {
+			if kv.Key == semconv.ExceptionStacktraceKey && kv.Value.AsString() != "" {
+				recorded = true
+			}
+		}
+	}
+	if !recorded {
+		t.Error("서버 span에 스택 트레이스가 있는 exception 이벤트가 없습니다")
+	}
+}
+
+func TestRateLimitRejectsBurst(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RPS", "2")
+	t.Setenv("ERROR_RATE", "0")
+	h := newHarness(t)
+
+	// 버킷 크기는 1초 분량(2개)이므로 바로 이어진 10개 중 대부분은 거절된다
+	var limited int
+	for i := 0; i < 10; i++ {
+		resp, _ := h.get(t, "/error")
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			limited++
+			if resp.Header.Get("Retry-After") == "" {
+				t.Error("429 응답에 Retry-After 헤더가 없습니다")
+			}
+		case http.StatusOK:
+		default:
+			t.Fatalf("GET /error = %d, want 200 또는 429", resp.StatusCode)
+		}
+	}
+	if limited < 5 {
+		t.Errorf("429 응답 %d개, 10개 중 5개 이상이어야 합니다", limited)
+	}
+
+	var rateLimitedSpans int
+	for _, s := range h.spans() {
+		if s.Name != "error" {
+			continue
+		}
+		if v, _ := spanAttr(s, "rate_limited"); v == true {
+			rateLimitedSpans++
+		}
+	}
+	if rateLimitedSpans != limited {
+		t.Errorf("rate_limited=true인 서버 span %d개, want %d", rateLimitedSpans, limited)
+	}
+}
+
+func TestServerSpanRecordsRegisteredRoute(t *testing.T) {
+	t.Setenv("SLOW_MIN_MS", "1")
+	t.Setenv("SLOW_MAX_MS", "1")
+	h := newHarness(t)
+
+	// 쿼리나 등록되지 않은 하위 경로가 있어도 등록한 패턴을 기록한다
+	h.get(t, "/slow?delay=1")
+	h.get(t, "/no/such/page")
+
+	if got, _ := spanAttr(h.span(t, "slow"), "http.route"); got != "/slow" {
+		t.Errorf("slow 서버 span http.route = %v, want /slow", got)
+	}
+	if got, _ := spanAttr(h.span(t, "home"), "http.route"); got != "/" {
+		t.Errorf("home 서버 span http.route = %v, want /", got)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"log/slog"
+	"math"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+
+	"observability-playground/internal/config"
+)
+
+// 경로별 초당 허용 요청 수 (0이면 제한 없음)
+var rateLimitRPS float64
+
+// RATE_LIMIT_RPS 환경 변수 파싱 (기본값 0 = 제한 없음)
+func initRateLimit() {
+	rateLimitRPS = config.NonNegativeFloat("RATE_LIMIT_RPS", 0)
+	if rateLimitRPS > 0 {
+		log.Printf("/slow, /error 요청을 경로별로 초당 %v개로 제한합니다.", rateLimitRPS)
+	}
+}
+
+// 토큰 버킷으로 요청 수를 제한하는 핸들러 래퍼 (경로마다 버킷을 따로 둔다)
+// 한도를 넘은 요청은 429로 거절하고 span에 rate_limited=true를 남겨 배압 상황을 흉내 낸다
+func withRateLimit(next http.HandlerFunc) http.HandlerFunc {
+	if rateLimitRPS <= 0 {
+		return next
+	}
+	// 1초 분량까지는 몰려도 허용
+	limiter := rate.NewLimiter(rate.Limit(rateLimitRPS), int(math.Max(1, math.Ceil(rateLimitRPS))))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		if !limiter.Allow() {
+			span.SetAttributes(attribute.Bool("rate_limited", true))
+			slog.WarnContext(r.Context(), "요청 수 제한 초과", "method", r.Method, "path", r.URL.Path)
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "요청이 너무 많습니다.", http.StatusTooManyRequests)
+			return
+		}
+		span.SetAttributes(attribute.Bool("rate_limited", false))
+		next(w, r)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 허용 목록에 있는 baggage 항목을 모든 span의 속성으로 올리는 SpanProcessor
+//
+// 승격 규칙:
+//   - TRACE_LABEL_ALLOWLIST(쉼표 구분, 기본값 "experiment,request.origin")에 있는 키만 승격한다
+//   - 허용 목록에 "*"가 있으면 모든 baggage 항목을 승격한다 (디버깅용)
+//   - 속성 이름은 baggage 키를 그대로 쓴다 (예: experiment=A -> span 속성 experiment="A")
+//   - 허용 목록에 없는 키는 속성 폭증을 막기 위해 무시한다
+//   - span이 시작될 때의 context에 있는 baggage를 기준으로 하므로 핸들러 안의 하위 span에도 붙는다
+type baggageAttributeProcessor struct {
+	allowed  map[string]bool
+	allowAll bool
+}
+
+func newBaggageAttributeProcessor() *baggageAttributeProcessor {
+	v, ok := os.LookupEnv("TRACE_LABEL_ALLOWLIST")
+	if !ok {
+		v = "experiment,request.origin"
+	}
+
+	allowed := make(map[string]bool)
+	for _, key := range strings.Split(v, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			allowed[key] = true
+		}
+	}
+	if len(allowed) > 0 {
+		log.Printf("baggage에서 span 속성으로 승격할 키: %s", v)
+	}
+	return &baggageAttributeProcessor{allowed: allowed, allowAll: allowed["*"]}
+}
+
+func (p *baggageAttributeProcessor) OnStart(ctx context.Context, s sdktrace.ReadWriteSpan) {
+	for _, member := range baggage.FromContext(ctx).Members() {
+		if p.allowAll || p.allowed[member.Key()] {
+			s.SetAttributes(attribute.String(member.Key(), member.Value()))
+		}
+	}
+}
+
+func (p *baggageAttributeProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (p *baggageAttributeProcessor) Shutdown(context.Context) error   { return nil }
+func (p *baggageAttributeProcessor) ForceFlush(context.Context) error { return nil }
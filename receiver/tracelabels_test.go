@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggage를 실은 요청을 path로 보낸다 (sender처럼 otelhttp transport로 전파)
+func getWithBaggage(t *testing.T, h *harness, path string, members map[string]string) {
+	t.Helper()
+	bag := baggage.FromContext(context.Background())
+	for k, v := range members {
+		member, err := baggage.NewMember(k, v)
+		if err != nil {
+			t.Fatalf("baggage 항목 %s: %v", k, err)
+		}
+		if bag, err = bag.SetMember(member); err != nil {
+			t.Fatalf("baggage 설정: %v", err)
+		}
+	}
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("요청 생성: %v", err)
+	}
+	client := &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	resp.Body.Close()
+}
+
+func TestBaggageRoundTripsOntoReceiverSpans(t *testing.T) {
+	h := newHarness(t)
+	getWithBaggage(t, h, "/", map[string]string{"request.origin": "sender", "tenant": "acme"})
+
+	for _, name := range []string{"home", "home-handler"} {
+		s := h.span(t, name)
+		if got, _ := spanAttr(s, "request.origin"); got != "sender" {
+			t.Errorf("%s span request.origin = %v, want sender", name, got)
+		}
+		// 기본 허용 목록에 없는 키는 승격하지 않는다
+		if got, ok := spanAttr(s, "tenant"); ok {
+			t.Errorf("%s span tenant = %v, want 없음", name, got)
+		}
+	}
+}
+
+func TestBaggageAllowlistWildcardPromotesAllMembers(t *testing.T) {
+	t.Setenv("TRACE_LABEL_ALLOWLIST", "*")
+	h := newHarness(t)
+	getWithBaggage(t, h, "/", map[string]string{"tenant": "acme"})
+
+	if got, _ := spanAttr(h.span(t, "home"), "tenant"); got != "acme" {
+		t.Errorf("home span tenant = %v, want acme", got)
+	}
+}
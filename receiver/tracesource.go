@@ -0,0 +1,31 @@
+package main
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// trace가 어디서 시작되었는지 나타내는 속성/baggage 키
+//
+// sender의 더미 요청 생성기는 baggage에 trace.source=generator를 실어 보낸다.
+// 표시가 없는 요청은 외부에서 들어온 것으로 보고 trace.source=external을 기록한다.
+const (
+	traceSourceKey      = "trace.source"
+	traceSourceExternal = "external"
+)
+
+// 들어온 baggage를 보고 서버 span에 trace.source를 기록하는 미들웨어
+func withTraceSource(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := baggage.FromContext(r.Context()).Member(traceSourceKey).Value()
+		if source == "" {
+			source = traceSourceExternal
+		}
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String(traceSourceKey, source))
+
+		next.ServeHTTP(w, r)
+	})
+}
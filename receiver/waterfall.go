@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/service"
+)
+
+// 워터폴의 하위 span 하나 (부모 시작 시각 기준 오프셋과 길이)
+type waterfallStep struct {
+	Name     string `json:"name"`
+	OffsetMs int    `json:"offset_ms"`
+	Duration int    `json:"duration_ms"`
+}
+
+// 기본 일정: 병렬로 실행되는 두 단계와 그 뒤에 이어지는 한 단계
+const defaultWaterfallSchedule = "fetch-user:0:100,fetch-orders:0:150,render:150:50"
+
+// "이름:오프셋ms:길이ms,..." 형식의 일정 파싱
+func parseWaterfallSchedule(v string) ([]waterfallStep, error) {
+	var steps []waterfallStep
+	for _, item := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("잘못된 일정 항목 %q (형식: 이름:오프셋ms:길이ms)", item)
+		}
+		offset, err := strconv.Atoi(parts[1])
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("잘못된 오프셋 %q", parts[1])
+		}
+		duration, err := strconv.Atoi(parts[2])
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("잘못된 길이 %q", parts[2])
+		}
+		steps = append(steps, waterfallStep{Name: parts[0], OffsetMs: offset, Duration: duration})
+	}
+	return steps, nil
+}
+
+// WATERFALL_SCHEDULE 환경 변수로 일정을 읽는다 (잘못되면 기본 일정)
+func getWaterfallSchedule() []waterfallStep {
+	if v := os.Getenv("WATERFALL_SCHEDULE"); v != "" {
+		steps, err := parseWaterfallSchedule(v)
+		if err == nil {
+			return steps
+		}
+		log.Printf("잘못된 WATERFALL_SCHEDULE 값, 기본 일정을 사용합니다: %v", err)
+	}
+	steps, _ := parseWaterfallSchedule(defaultWaterfallSchedule)
+	return steps
+}
+
+// 정확한 시작/종료 시각을 지정한 하위 span으로 교과서 같은 워터폴을 만드는 핸들러
+// 실제로 기다리지 않고 타임스탬프를 직접 지정하므로 trace 모양이 항상 같다
+func waterfallHandler(w http.ResponseWriter, r *http.Request) {
+	steps := getWaterfallSchedule()
+
+	total := 0
+	for _, step := range steps {
+		total = max(total, step.OffsetMs+step.Duration)
+	}
+
+	base := time.Now()
+	ctx, span := tracer.Start(r.Context(), "waterfall", trace.WithTimestamp(base))
+	span.SetAttributes(
+		attribute.String("waterfall.schedule", formatWaterfallSchedule(steps)),
+		attribute.Int("waterfall.total_ms", total),
+	)
+
+	for _, step := range steps {
+		start := base.Add(time.Duration(step.OffsetMs) * time.Millisecond)
+		_, child := tracer.Start(ctx, step.Name, trace.WithTimestamp(start))
+		child.SetAttributes(
+			attribute.Int("waterfall.offset_ms", step.OffsetMs),
+			attribute.Int("waterfall.duration_ms", step.Duration),
+		)
+		child.End(trace.WithTimestamp(start.Add(time.Duration(step.Duration) * time.Millisecond)))
+	}
+
+	span.End(trace.WithTimestamp(base.Add(time.Duration(total) * time.Millisecond)))
+
+	service.LogWithTrace(r.Context(), "워터폴 생성", "steps", len(steps), "total_ms", total)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"trace_id": span.SpanContext().TraceID().String(),
+		"total_ms": total,
+		"steps":    steps,
+	})
+}
+
+func formatWaterfallSchedule(steps []waterfallStep) string {
+	items := make([]string, len(steps))
+	for i, step := range steps {
+		items[i] = fmt.Sprintf("%s:%d:%d", step.Name, step.OffsetMs, step.Duration)
+	}
+	return strings.Join(items, ",")
+}
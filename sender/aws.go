@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+)
+
+// span 속성에 기록할 인스턴스 ID 개수의 상한 (속성 크기 제한)
+const maxRecordedInstanceIDsCap = 50
+
+// DescribeInstances 결과 중 span에 기록할 인스턴스 ID 개수 (AWS_RECORD_INSTANCE_IDS, 기본값 5)
+func getRecordedInstanceIDs() int {
+	k := config.PositiveInt("AWS_RECORD_INSTANCE_IDS", 5)
+	if k > maxRecordedInstanceIDsCap {
+		log.Printf("AWS_RECORD_INSTANCE_IDS 값 %d가 상한 %d를 넘어 제한합니다.", k, maxRecordedInstanceIDsCap)
+		k = maxRecordedInstanceIDsCap
+	}
+	return k
+}
+
+// OpenTelemetry 미들웨어가 추가된 EC2 클라이언트 생성
+// 자격 증명과 리전은 AWS SDK 기본 체인(환경 변수, 프로필, IMDS 등)에서 읽는다
+func newEC2Client(ctx context.Context) (*ec2.Client, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+	return ec2.NewFromConfig(cfg), nil
+}
+
+// 모든 페이지의 EC2 인스턴스를 조회하고 처음 K개의 인스턴스 ID를 span에 기록하는 핸들러
+// 각 DescribeInstances 호출은 otelaws가 만든 하위 span으로 나타난다
+func awsInstancesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracer.Start(r.Context(), "aws-describe-instances")
+	defer span.End()
+
+	client, err := newEC2Client(ctx)
+	if err != nil {
+		slog.Error("AWS 설정 로드 실패", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "AWS 설정 로드 실패")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var ids []string
+	pages := 0
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			slog.Error("DescribeInstances 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "DescribeInstances 실패")
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		pages++
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				ids = append(ids, aws.ToString(instance.InstanceId))
+			}
+		}
+	}
+
+	recorded := ids
+	if k := getRecordedInstanceIDs(); len(recorded) > k {
+		recorded = recorded[:k]
+	}
+	span.SetAttributes(
+		attribute.Int("aws.ec2.pages", pages),
+		attribute.Int("aws.ec2.instance_count", len(ids)),
+		attribute.StringSlice("aws.ec2.instance_ids", recorded),
+	)
+
+	service.LogWithTrace(r.Context(), "EC2 인스턴스 조회 완료", "instances", len(ids), "pages", pages)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"count":        len(ids),
+		"pages":        pages,
+		"instance_ids": ids,
+	})
+}
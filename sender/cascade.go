@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/service"
+)
+
+// receiver를 거쳐 다운스트림까지 이어지는 연쇄 장애를 재현하는 핸들러
+// 장애 지점은 fail_at 쿼리(없으면 CASCADE_FAIL_AT, 기본값 downstream)로 지정한다
+// 가능한 값: receiver, downstream, none
+func cascadeHandler(receiverEndpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "cascade")
+		defer span.End()
+
+		failAt := r.URL.Query().Get("fail_at")
+		if failAt == "" {
+			failAt = os.Getenv("CASCADE_FAIL_AT")
+		}
+		if failAt == "" {
+			failAt = "downstream"
+		}
+		span.SetAttributes(attribute.String("cascade.fail_at", failAt))
+
+		service.LogWithTrace(ctx, "연쇄 장애 요청", "fail_at", failAt)
+
+		reqURL := fmt.Sprintf("%s/cascade?fail_at=%s", receiverEndpoint, url.QueryEscape(failAt))
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			slog.Error("연쇄 요청 생성 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "연쇄 요청 생성 실패")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		resp, err := downstreamClient.Do(req)
+		if err != nil {
+			slog.Error("연쇄 요청 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "receiver 호출 실패")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "receiver 호출 실패: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+
+		span.SetAttributes(attribute.Int("cascade.upstream_status", resp.StatusCode))
+
+		// 하위 단계의 실패를 이 단계의 실패로 전파
+		if resp.StatusCode >= http.StatusInternalServerError {
+			slog.Warn("연쇄 장애 전파", "receiver_status", resp.StatusCode)
+			span.SetStatus(codes.Error, fmt.Sprintf("receiver 응답 %d", resp.StatusCode))
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "sender: 하위 단계 실패\n%s", body)
+			return
+		}
+
+		fmt.Fprintf(w, "sender: 연쇄 요청 성공\n%s", body)
+	}
+}
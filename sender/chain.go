@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/service"
+)
+
+// CHAIN_DOWNSTREAMS(쉼표 구분, 기본값 "/slow,/error,/")에 지정한 하위 경로 목록
+// "/"로 시작하는 값은 RECEIVER_ENDPOINT 기준 경로로, 그 밖의 값은 전체 URL로 취급한다
+func getChainDownstreams(receiverEndpoint string) []string {
+	v := os.Getenv("CHAIN_DOWNSTREAMS")
+	if strings.TrimSpace(v) == "" {
+		v = "/slow,/error,/"
+	}
+
+	var urls []string
+	for _, target := range strings.Split(v, ",") {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		if strings.HasPrefix(target, "/") {
+			target = receiverEndpoint + target
+		}
+		urls = append(urls, target)
+	}
+	return urls
+}
+
+// 하위 서비스를 차례로 호출해 한 trace 안에 여러 단계를 만드는 핸들러
+// 각 호출은 chain-step span 아래에서 이루어지므로 Tempo에서 단계별로 나뉘어 보인다
+// 한 단계가 실패해도 나머지 단계는 계속 호출하고, 실패한 단계 수를 응답과 span에 기록한다
+func chainHandler(receiverEndpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "chain")
+		defer span.End()
+
+		downstreams := getChainDownstreams(receiverEndpoint)
+		span.SetAttributes(attribute.Int("chain.length", len(downstreams)))
+		service.LogWithTrace(ctx, "체인 요청", "downstreams", len(downstreams))
+
+		failed := 0
+		for i, target := range downstreams {
+			stepCtx, step := tracer.Start(ctx, "chain-step")
+			step.SetAttributes(
+				attribute.Int("chain.step", i+1),
+				attribute.String("chain.url", target),
+			)
+
+			status, err := func() (int, error) {
+				req, err := http.NewRequestWithContext(stepCtx, "GET", target, nil)
+				if err != nil {
+					return 0, err
+				}
+				resp, err := downstreamClient.Do(req)
+				if err != nil {
+					return 0, err
+				}
+				defer resp.Body.Close()
+				io.Copy(io.Discard, resp.Body)
+				return resp.StatusCode, nil
+			}()
+
+			switch {
+			case err != nil:
+				failed++
+				slog.ErrorContext(stepCtx, "체인 단계 호출 실패", "url", target, "error", err)
+				step.RecordError(err)
+				step.SetStatus(codes.Error, "하위 서비스 호출 실패")
+				fmt.Fprintf(w, "%d. %s: 실패 (%v)\n", i+1, target, err)
+			case status >= http.StatusInternalServerError:
+				failed++
+				step.SetAttributes(attribute.Int("chain.status", status))
+				step.SetStatus(codes.Error, fmt.Sprintf("하위 서비스 응답 %d", status))
+				fmt.Fprintf(w, "%d. %s: %d\n", i+1, target, status)
+			default:
+				step.SetAttributes(attribute.Int("chain.status", status))
+				fmt.Fprintf(w, "%d. %s: %d\n", i+1, target, status)
+			}
+			step.End()
+		}
+
+		span.SetAttributes(attribute.Int("chain.failed_steps", failed))
+		if failed > 0 {
+			span.SetStatus(codes.Error, fmt.Sprintf("%d개 단계 실패", failed))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestChainCreatesOneChildSpanPerDownstream(t *testing.T) {
+	t.Setenv("CHAIN_DOWNSTREAMS", "/slow,/error,/")
+	exporter := newTestTracer(t)
+	initHTTPClients()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/error" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	rec := httptest.NewRecorder()
+	chainHandler(receiver.URL)(rec, httptest.NewRequest(http.MethodGet, "/chain", nil))
+
+	spans := exporter.GetSpans()
+	chain := findSpan(t, spans, "chain")
+	steps := map[trace.SpanID]bool{}
+	for _, s := range spans {
+		if s.Name == "chain-step" && s.Parent.SpanID() == chain.SpanContext.SpanID() {
+			steps[s.SpanContext.SpanID()] = true
+		}
+	}
+	if len(steps) != 3 {
+		t.Fatalf("chain 아래 chain-step span = %d개, want 3", len(steps))
+	}
+
+	// 단계마다 계측된 클라이언트가 HTTP 요청 span을 하나씩 만든다
+	requests := 0
+	for _, s := range spans {
+		if s.SpanKind == trace.SpanKindClient && steps[s.Parent.SpanID()] {
+			requests++
+		}
+	}
+	if requests != 3 {
+		t.Errorf("chain-step 아래 HTTP 클라이언트 span = %d개, want 3", requests)
+	}
+	for _, kv := range chain.Attributes {
+		if kv.Key == "chain.failed_steps" && kv.Value.AsInt64() != 1 {
+			t.Errorf("chain.failed_steps = %d, want 1 (/error)", kv.Value.AsInt64())
+		}
+	}
+}
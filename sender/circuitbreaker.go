@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 서킷이 열려 있어 요청을 보내지 않았을 때 반환하는 에러
+var errCircuitOpen = errors.New("서킷 브레이커 열림: 요청 차단")
+
+// 서킷 브레이커 상태
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// 대상(host) 하나에 대한 서킷 브레이커
+type circuitBreaker struct {
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool // half-open 상태에서 시험 요청이 진행 중인지
+}
+
+// 대상별 서킷 브레이커를 적용하는 RoundTripper
+// threshold가 0이면 비활성화되어 모든 요청을 그대로 통과시킨다
+type circuitBreakerTransport struct {
+	base      http.RoundTripper
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// 환경 변수에서 서킷 브레이커 설정을 읽어 RoundTripper 생성
+// CIRCUIT_BREAKER_THRESHOLD: 서킷을 여는 연속 실패 횟수 (기본값 0 = 비활성화)
+// CIRCUIT_BREAKER_COOLDOWN: 서킷이 열린 뒤 다시 시도하기까지의 대기 시간 (기본값 30s)
+func newCircuitBreakerTransport(base http.RoundTripper) *circuitBreakerTransport {
+	threshold := config.NonNegativeInt("CIRCUIT_BREAKER_THRESHOLD", 0)
+	cooldown := config.PositiveDuration("CIRCUIT_BREAKER_COOLDOWN", 30*time.Second)
+
+	if threshold > 0 {
+		log.Printf("서킷 브레이커 활성화 (연속 실패 %d회, 대기 %v)", threshold, cooldown)
+	}
+
+	return &circuitBreakerTransport{
+		base:      base,
+		threshold: threshold,
+		cooldown:  cooldown,
+		breakers:  make(map[string]*circuitBreaker),
+	}
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.threshold <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	span := trace.SpanFromContext(req.Context())
+	cb := t.breakerFor(req.URL.Host)
+
+	if !cb.allow(span, t.cooldown) {
+		return nil, errCircuitOpen
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	cb.record(span, t.threshold, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+// 대상 host에 해당하는 서킷 브레이커 반환 (없으면 생성)
+func (t *circuitBreakerTransport) breakerFor(host string) *circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cb, ok := t.breakers[host]
+	if !ok {
+		cb = &circuitBreaker{}
+		t.breakers[host] = cb
+	}
+	return cb
+}
+
+// 요청을 보내도 되는지 확인하고 현재 상태를 span에 기록
+// half-open 상태에서는 시험 요청 하나만 통과시키고, 그 결과가 나올 때까지 나머지는 막는다
+func (cb *circuitBreaker) allow(span trace.Span, cooldown time.Duration) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == circuitOpen && time.Since(cb.openedAt) >= cooldown {
+		cb.transition(span, circuitHalfOpen)
+	}
+	span.SetAttributes(attribute.String("circuit.state", cb.state.String()))
+
+	switch cb.state {
+	case circuitOpen:
+		return false
+	case circuitHalfOpen:
+		if cb.probing {
+			return false
+		}
+		cb.probing = true
+	}
+	return true
+}
+
+// 요청 결과를 반영해 상태를 갱신
+func (cb *circuitBreaker) record(span trace.Span, threshold int, success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probing = false
+
+	if success {
+		cb.failures = 0
+		if cb.state != circuitClosed {
+			cb.transition(span, circuitClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= threshold {
+		cb.openedAt = time.Now()
+		if cb.state != circuitOpen {
+			cb.transition(span, circuitOpen)
+		}
+	}
+}
+
+// 상태를 변경하고 span 이벤트로 남김 (cb.mu를 잡은 상태에서 호출)
+func (cb *circuitBreaker) transition(span trace.Span, to circuitState) {
+	from := cb.state
+	cb.state = to
+
+	log.Printf("서킷 브레이커 상태 변경: %s -> %s", from, to)
+	span.AddEvent("circuit.state_change", trace.WithAttributes(
+		attribute.String("circuit.from", from.String()),
+		attribute.String("circuit.to", to.String()),
+		attribute.Int("circuit.failures", cb.failures),
+	))
+}
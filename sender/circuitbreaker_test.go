@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTripsHalfOpensAndCloses(t *testing.T) {
+	t.Setenv("CIRCUIT_BREAKER_THRESHOLD", "2")
+	t.Setenv("CIRCUIT_BREAKER_COOLDOWN", "50ms")
+
+	var hits atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		<-release // 시험 요청이 진행 중인 동안 다른 요청이 막히는지 보기 위해 붙잡아 둔다
+	}))
+	t.Cleanup(srv.Close)
+
+	cbt := newCircuitBreakerTransport(&http.Transport{})
+	client := &http.Client{Transport: cbt}
+	host, _ := url.Parse(srv.URL)
+	state := func() circuitState {
+		cb := cbt.breakerFor(host.Host)
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		return cb.state
+	}
+
+	// 연속 실패 2회로 서킷이 열린다
+	for range 2 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if got := state(); got != circuitOpen {
+		t.Fatalf("실패 2회 후 상태 = %s, want open", got)
+	}
+	if _, err := client.Get(srv.URL); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("열린 서킷의 요청 오류 = %v, want errCircuitOpen", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("서버 요청 수 = %d, want 2 (열린 동안 차단)", got)
+	}
+
+	// 대기 시간이 지나면 half-open이 되어 시험 요청 하나만 보낸다
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+	probeDone := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		probeDone <- err
+	}()
+	for hits.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := state(); got != circuitHalfOpen {
+		t.Errorf("시험 요청 중 상태 = %s, want half-open", got)
+	}
+	if _, err := client.Get(srv.URL); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("시험 요청 중 두 번째 요청 오류 = %v, want errCircuitOpen", err)
+	}
+
+	// 시험 요청이 성공하면 서킷이 닫히고 다시 모든 요청을 보낸다
+	close(release)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("시험 요청: %v", err)
+	}
+	if got := state(); got != circuitClosed {
+		t.Errorf("시험 요청 성공 후 상태 = %s, want closed", got)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("닫힌 서킷의 요청: %v", err)
+	}
+	resp.Body.Close()
+	if got := hits.Load(); got != 4 {
+		t.Errorf("서버 요청 수 = %d, want 4", got)
+	}
+}
+
+func TestCircuitBreakerReopensWhenProbeFails(t *testing.T) {
+	t.Setenv("CIRCUIT_BREAKER_THRESHOLD", "1")
+	t.Setenv("CIRCUIT_BREAKER_COOLDOWN", "20ms")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	cbt := newCircuitBreakerTransport(&http.Transport{})
+	client := &http.Client{Transport: cbt}
+
+	for range 2 { // 첫 실패로 열리고, 대기 후 시험 요청도 실패
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		resp.Body.Close()
+		time.Sleep(30 * time.Millisecond)
+	}
+	host, _ := url.Parse(srv.URL)
+	if got := cbt.breakerFor(host.Host).state; got != circuitOpen {
+		t.Errorf("시험 요청 실패 후 상태 = %s, want open", got)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 더미 요청이 기본으로 고르는 receiver 엔드포인트
+var defaultDummyEndpoints = []string{"/", "/health", "/feature"}
+
+// 가중치에 비례해 엔드포인트를 고르는 선택기
+type endpointPicker struct {
+	paths   []string
+	weights []int // 누적 가중치
+	total   int
+}
+
+// 더미 요청 엔드포인트 선택기 (generateDummyTraces가 사용)
+var dummyEndpoints *endpointPicker
+
+// DUMMY_ENDPOINTS(쉼표 구분, 예: "/,/panic,/chain")로 지정한 엔드포인트 목록
+// "/"로 시작하지 않는 항목은 무시하고, 값이 없거나 유효한 항목이 없으면 기본 엔드포인트를 사용한다
+func getDummyEndpoints() []string {
+	v := os.Getenv("DUMMY_ENDPOINTS")
+	if v == "" {
+		return defaultDummyEndpoints
+	}
+
+	var paths []string
+	for _, path := range strings.Split(v, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") {
+			log.Printf("잘못된 더미 엔드포인트 %q (\"/\"로 시작해야 함), 무시합니다.", path)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		log.Printf("DUMMY_ENDPOINTS에 유효한 항목이 없어 기본 엔드포인트를 사용합니다.")
+		return defaultDummyEndpoints
+	}
+	log.Printf("더미 요청 엔드포인트: %s", strings.Join(paths, ","))
+	return paths
+}
+
+// DUMMY_ENDPOINT_WEIGHTS(예: "/:1,/slow:3,/error:2")로 선택기 생성
+// 경로는 DUMMY_ENDPOINTS와 마찬가지로 "/"로 시작해야 하며, 그렇지 않은 항목은 무시한다
+// 값이 없거나 유효한 항목이 하나도 없으면 DUMMY_ENDPOINTS(없으면 기본 엔드포인트)를 균등하게 고른다
+func newEndpointPicker() *endpointPicker {
+	p := &endpointPicker{}
+	if v := os.Getenv("DUMMY_ENDPOINT_WEIGHTS"); v != "" {
+		for _, pair := range strings.Split(v, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			i := strings.LastIndex(pair, ":")
+			if i <= 0 {
+				log.Printf("잘못된 엔드포인트 가중치 %q (형식: path:weight), 무시합니다.", pair)
+				continue
+			}
+			path := strings.TrimSpace(pair[:i])
+			if !strings.HasPrefix(path, "/") {
+				log.Printf("잘못된 더미 엔드포인트 %q (\"/\"로 시작해야 함), 무시합니다.", path)
+				continue
+			}
+			weight, err := strconv.Atoi(strings.TrimSpace(pair[i+1:]))
+			if err != nil || weight <= 0 {
+				log.Printf("잘못된 엔드포인트 가중치 %q, 무시합니다.", pair)
+				continue
+			}
+			p.add(path, weight)
+		}
+		if p.total == 0 {
+			log.Printf("DUMMY_ENDPOINT_WEIGHTS에 유효한 항목이 없어 엔드포인트를 균등하게 사용합니다.")
+		} else {
+			log.Printf("더미 요청 엔드포인트 가중치: %s", v)
+		}
+	}
+
+	if p.total == 0 {
+		for _, path := range getDummyEndpoints() {
+			p.add(path, 1)
+		}
+	}
+	return p
+}
+
+func (p *endpointPicker) add(path string, weight int) {
+	p.total += weight
+	p.paths = append(p.paths, path)
+	p.weights = append(p.weights, p.total)
+}
+
+// 가중치에 따라 엔드포인트 하나를 고른다
+func (p *endpointPicker) pick() string {
+	n := rand.Intn(p.total)
+	for i, cum := range p.weights {
+		if n < cum {
+			return p.paths[i]
+		}
+	}
+	return p.paths[len(p.paths)-1]
+}
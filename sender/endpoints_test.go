@@ -0,0 +1,50 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewEndpointPicker(t *testing.T) {
+	tests := []struct {
+		name      string
+		weights   string
+		endpoints string
+		wantPaths []string
+		wantTotal int
+	}{
+		{"설정 없음", "", "", defaultDummyEndpoints, len(defaultDummyEndpoints)},
+		{"가중치", "/:1,/slow:3,/error:2", "", []string{"/", "/slow", "/error"}, 6},
+		{"잘못된 항목은 무시", "/:1,/slow,/error:0,/cpu:x,/feature:2", "", []string{"/", "/feature"}, 3},
+		{"\"/\"로 시작하지 않는 경로는 무시", "slow:3,http://x/:2,/error:1", "", []string{"/error"}, 1},
+		{"유효한 항목이 없으면 DUMMY_ENDPOINTS 균등", "/slow:-1", "/a,/b", []string{"/a", "/b"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DUMMY_ENDPOINT_WEIGHTS", tt.weights)
+			t.Setenv("DUMMY_ENDPOINTS", tt.endpoints)
+			p := newEndpointPicker()
+			if !reflect.DeepEqual(p.paths, tt.wantPaths) || p.total != tt.wantTotal {
+				t.Errorf("paths = %v (합계 %d), want %v (합계 %d)", p.paths, p.total, tt.wantPaths, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestEndpointPickerFavorsHeavyWeight(t *testing.T) {
+	t.Setenv("DUMMY_ENDPOINT_WEIGHTS", "/:1,/slow:98,/error:1")
+	p := newEndpointPicker()
+
+	counts := map[string]int{}
+	const picks = 2000
+	for i := 0; i < picks; i++ {
+		counts[p.pick()]++
+	}
+	// 기대값은 /slow 1960회, 나머지 각 20회
+	if counts["/slow"] < picks*9/10 {
+		t.Errorf("/slow가 %d/%d회 선택됨, 90%% 이상이어야 합니다 (%v)", counts["/slow"], picks, counts)
+	}
+	if counts["/slow"] <= counts["/"]*10 || counts["/slow"] <= counts["/error"]*10 {
+		t.Errorf("가중치가 큰 /slow가 충분히 자주 선택되지 않았습니다: %v", counts)
+	}
+}
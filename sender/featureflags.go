@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggage에 실을 때 기능 플래그 이름 앞에 붙이는 접두사
+const featureFlagPrefix = "feature."
+
+// FEATURE_FLAGS 환경 변수(예: "new-greeting=on,extra-latency=off")를 baggage 항목으로 변환
+// 잘못된 항목은 경고를 남기고 건너뛴다
+func parseFeatureFlags(v string) []baggage.Member {
+	var members []baggage.Member
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			value = "on" // 값이 없으면 켜진 것으로 간주
+		}
+		member, err := baggage.NewMember(featureFlagPrefix+strings.TrimSpace(name), strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("잘못된 기능 플래그 %q, 무시합니다: %v", pair, err)
+			continue
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// 시작 시 한 번 읽어 둔 기능 플래그
+var featureFlags = parseFeatureFlags(os.Getenv("FEATURE_FLAGS"))
+
+// 기능 플래그를 baggage에 넣은 context 반환 (receiver까지 전파됨)
+func withFeatureFlags(ctx context.Context) context.Context {
+	return withBaggageMembers(ctx, featureFlags)
+}
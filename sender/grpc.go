@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"observability-playground/internal/echo"
+	"observability-playground/internal/service"
+)
+
+// receiver의 gRPC Echo 서비스 연결 (nil이면 gRPC 호출을 하지 않음)
+var echoConn *grpc.ClientConn
+
+// GRPC_ECHO_ENDPOINT(예: receiver:9091)가 설정되면 gRPC 클라이언트 연결 생성
+// 더미 요청마다 HTTP 요청과 함께 Echo RPC도 호출해 두 trace 모양을 비교할 수 있다
+func initEchoClient() error {
+	endpoint := os.Getenv("GRPC_ECHO_ENDPOINT")
+	if endpoint == "" {
+		return nil
+	}
+
+	conn, err := grpc.NewClient(endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()), // 테스트 환경에서는 TLS 없이 설정
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return fmt.Errorf("gRPC 클라이언트 생성 실패: %w", err)
+	}
+	echoConn = conn
+	log.Printf("더미 요청마다 %s로 gRPC Echo도 호출합니다.", endpoint)
+	return nil
+}
+
+// Echo RPC 호출 (연결이 없으면 아무것도 하지 않음)
+func callEchoRPC(ctx context.Context, message string) {
+	if echoConn == nil {
+		return
+	}
+	reply, err := echo.Call(ctx, echoConn, message)
+	if err != nil {
+		slog.ErrorContext(ctx, "gRPC Echo 호출 실패", "error", err)
+		return
+	}
+	service.LogWithTrace(ctx, "gRPC Echo 완료", "reply", reply)
+}
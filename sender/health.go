@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"observability-playground/internal/config"
+)
+
+// 마지막으로 더미 요청이 receiver의 응답을 받은 시각 (UnixNano)
+// 시작 직후에는 아직 요청이 없으므로 main에서 시작 시각으로 초기화한다
+var lastDummySuccess atomic.Int64
+
+// 더미 요청 성공을 기록 (5xx가 아닌 응답을 받았을 때)
+func markDummySuccess() {
+	lastDummySuccess.Store(time.Now().UnixNano())
+}
+
+// 마지막 더미 요청 성공 후 허용하는 시간 (HEALTH_WINDOW, 기본값 30s)
+var healthWindow time.Duration
+
+func getHealthWindow() time.Duration {
+	return config.PositiveDuration("HEALTH_WINDOW", 30*time.Second)
+}
+
+// receiver에 도달할 수 있는지 보고하는 상태 확인 핸들러
+// 마지막 더미 요청 성공이 HEALTH_WINDOW 안이면 200, 아니면 503
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := tracer.Start(ctx, "health-handler")
+	defer span.End()
+
+	since := time.Since(time.Unix(0, lastDummySuccess.Load()))
+	span.SetAttributes(attribute.Int64("dummy.since_last_success_ms", since.Milliseconds()))
+	if since > healthWindow {
+		slog.WarnContext(ctx, "마지막 더미 요청 성공 후 허용 시간 초과", "since", since, "allowed", healthWindow)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "sender: 상태: 비정상 (마지막 receiver 응답 후 %v 경과)\n", since.Round(time.Second))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "sender: 상태: 정상\n")
+}
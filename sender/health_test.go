@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthReportsStaleDummySuccess(t *testing.T) {
+	t.Setenv("HEALTH_WINDOW", "1s")
+	newTestTracer(t)
+	initDummyRequests()
+	prev := lastDummySuccess.Load()
+	t.Cleanup(func() { lastDummySuccess.Store(prev) })
+
+	tests := []struct {
+		name        string
+		lastSuccess time.Time
+		want        int
+	}{
+		{"최근 성공", time.Now(), http.StatusOK},
+		{"허용 시간 초과", time.Now().Add(-time.Minute), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		lastDummySuccess.Store(tt.lastSuccess.UnixNano())
+		rec := httptest.NewRecorder()
+		healthHandler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if rec.Code != tt.want {
+			t.Errorf("%s: GET /health = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"observability-playground/internal/config"
+)
+
+// 매번 새로 만들지 않고 initHTTPClients에서 한 번 만들어 receiver와의 연결을 keep-alive로 재사용하는 클라이언트
+var (
+	// 더미 요청과 부하 테스트가 함께 쓰는 클라이언트 (서킷 브레이커 적용)
+	dummyClient *http.Client
+	// /cascade, /chain 등 핸들러가 하위 서비스를 호출할 때 쓰는 클라이언트
+	// 더미 요청과 연결 풀을 공유하지만, 데모용 장애가 더미 요청까지 막지 않도록 서킷 브레이커는 거치지 않는다
+	downstreamClient *http.Client
+)
+
+// 연결 풀을 공유하는 공용 클라이언트들을 만든다 (TracerProvider를 설정한 뒤에 호출)
+func initHTTPClients() {
+	pooled := newPooledTransport()
+	breakerTransport = newCircuitBreakerTransport(otelhttp.NewTransport(pooled))
+	dummyClient = &http.Client{Transport: breakerTransport}
+	downstreamClient = &http.Client{Transport: otelhttp.NewTransport(traceparentCapture{base: pooled})}
+}
+
+// 연결 풀 크기와 유휴 연결 유지 시간을 조정한 transport
+// DUMMY_HTTP_MAX_IDLE_CONNS: 유지할 유휴 연결 수 (기본값 100, 대상이 receiver 하나이므로 host별 한도도 같게 둔다)
+// DUMMY_HTTP_IDLE_CONN_TIMEOUT: 유휴 연결을 닫기까지의 시간 (기본값 90s)
+func newPooledTransport() *http.Transport {
+	maxIdle := config.PositiveInt("DUMMY_HTTP_MAX_IDLE_CONNS", 100)
+	idleTimeout := config.PositiveDuration("DUMMY_HTTP_IDLE_CONN_TIMEOUT", 90*time.Second)
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = maxIdle
+	t.MaxIdleConnsPerHost = maxIdle
+	t.IdleConnTimeout = idleTimeout
+	return t
+}
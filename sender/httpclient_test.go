@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHandlersReuseSharedClientConnections(t *testing.T) {
+	newTestTracer(t)
+	initHTTPClients()
+
+	// receiver 대신 새 연결 수를 세는 서버
+	var conns atomic.Int32
+	receiver := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	receiver.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	receiver.Start()
+	t.Cleanup(receiver.Close)
+	t.Setenv("CHAIN_DOWNSTREAMS", "/slow,/error,/")
+
+	// 여러 핸들러가 차례로 하위 요청을 보내도 공용 클라이언트의 연결 하나를 재사용한다
+	handlers := []http.HandlerFunc{
+		chainHandler(receiver.URL),
+		chainHandler(receiver.URL),
+		cascadeHandler(receiver.URL),
+		timeoutTestHandler(receiver.URL),
+	}
+	for _, h := range handlers {
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	if got := conns.Load(); got != 1 {
+		t.Errorf("receiver가 받은 연결 = %d, want 1 (공용 클라이언트의 연결 재사용)", got)
+	}
+}
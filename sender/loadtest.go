@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// 부하 테스트 결과 집계
+type loadTestResult struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+func (r *loadTestResult) record(latency time.Duration, status int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors++
+		return
+	}
+	r.latencies = append(r.latencies, latency)
+	r.statuses[status]++
+}
+
+// 정렬된 지연 시간에서 백분위수 계산
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(float64(len(sorted)-1) * p)
+	return sorted[idx]
+}
+
+// receiver를 대상으로 closed-loop 부하 테스트를 실행하고 결과를 출력
+// 사용법: sender loadtest -rps 50 -duration 30s -concurrency 8 -path /slow
+func runLoadTest(args []string, receiverEndpoint string) error {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	rps := fs.Float64("rps", 10, "목표 초당 요청 수 (0이면 제한 없음)")
+	duration := fs.Duration("duration", 30*time.Second, "부하 테스트 시간")
+	concurrency := fs.Int("concurrency", 4, "동시에 요청을 보내는 워커 수")
+	path := fs.String("path", "/", "요청할 receiver 경로")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *concurrency <= 0 || *duration <= 0 || *rps < 0 {
+		return fmt.Errorf("concurrency와 duration은 양수, rps는 0 이상이어야 합니다")
+	}
+
+	reqURL := receiverEndpoint + *path
+	log.Printf("부하 테스트 시작: %s, 목표 %.1f RPS, %v 동안, 워커 %d개", reqURL, *rps, *duration, *concurrency)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	// 목표 RPS에 맞춰 요청 허가를 내보낸다 (0이면 제한 없이 워커가 바로 요청)
+	var permits <-chan time.Time
+	if *rps > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *rps))
+		defer ticker.Stop()
+		permits = ticker.C
+	}
+
+	result := &loadTestResult{statuses: make(map[int]int)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if permits != nil {
+					select {
+					case <-permits:
+					case <-ctx.Done():
+						return
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+				latency, status, err := sendLoadTestRequest(ctx, dummyClient, reqURL)
+				if ctx.Err() != nil {
+					return // 테스트 종료로 취소된 요청은 집계하지 않음
+				}
+				result.record(latency, status, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	printLoadTestSummary(os.Stdout, result, *duration)
+	return nil
+}
+
+// 요청 하나를 보내고 지연 시간과 상태 코드를 반환
+func sendLoadTestRequest(ctx context.Context, client *http.Client, reqURL string) (time.Duration, int, error) {
+	ctx, span := tracer.Start(ctx, "loadtest-request")
+	defer span.End()
+	span.SetAttributes(attribute.String("loadtest.url", reqURL))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		span.RecordError(err)
+		return 0, 0, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "요청 실패")
+		return 0, 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	latency := time.Since(start)
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, fmt.Sprintf("응답 %d", resp.StatusCode))
+	}
+	return latency, resp.StatusCode, nil
+}
+
+// 부하 테스트 결과를 표로 출력
+func printLoadTestSummary(out io.Writer, result *loadTestResult, duration time.Duration) {
+	result.mu.Lock()
+	defer result.mu.Unlock()
+
+	sorted := append([]time.Duration(nil), result.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	total := len(sorted) + result.errors
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "항목\t값")
+	fmt.Fprintf(tw, "전체 요청\t%d\n", total)
+	fmt.Fprintf(tw, "전송 실패\t%d\n", result.errors)
+	fmt.Fprintf(tw, "실제 RPS\t%.1f\n", float64(total)/duration.Seconds())
+	for _, p := range []struct {
+		name  string
+		value float64
+	}{{"p50", 0.50}, {"p90", 0.90}, {"p95", 0.95}, {"p99", 0.99}, {"max", 1}} {
+		fmt.Fprintf(tw, "%s\t%v\n", p.name, percentile(sorted, p.value).Round(time.Microsecond))
+	}
+
+	statusCodes := make([]int, 0, len(result.statuses))
+	for code := range result.statuses {
+		statusCodes = append(statusCodes, code)
+	}
+	sort.Ints(statusCodes)
+	for _, code := range statusCodes {
+		fmt.Fprintf(tw, "상태 %d\t%d\n", code, result.statuses[code])
+	}
+	tw.Flush()
+}
@@ -2,147 +2,367 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"math/rand"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
-	"go.opentelemetry.io/otel"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/sdk/resource"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/service"
+	"observability-playground/internal/stats"
+	"observability-playground/internal/telemetry"
 )
 
 var tracer trace.Tracer
 
-func initTracer() (*sdktrace.TracerProvider, error) {
-	// OTLP exporter 생성
-	ctx := context.Background()
+// 더미 요청용 클라이언트가 사용하는 서킷 브레이커 transport
+var breakerTransport *circuitBreakerTransport
 
-	// Tempo 서버로 전송
-	tempoEndpoint := os.Getenv("TEMPO_ENDPOINT")
-	if tempoEndpoint == "" {
-		tempoEndpoint = "tempo:4317" // 기본값
+// sender 진단 서버의 모든 HTTP 핸들러를 등록한 mux를 만든다
+// receiver를 호출하는 핸들러는 cfg.ReceiverEndpoint를 대상으로 한다
+func newMux(cfg config.Config) *service.InstrumentedMux {
+	mux := service.NewInstrumentedMux(cfg.ServiceName)
+	handle(mux, "/health", "health", healthHandler)
+	handle(mux, "/version", "version", service.VersionHandler)
+	// 수집 요청까지 trace로 남지 않도록 미들웨어 없이 등록
+	mux.Handle("/metrics", telemetry.MetricsHandler())
+	mux.Handle("/stats", stats.Handler())
+	// 호스트 이름, 프로세스 정보 등 내부 정보가 드러나므로 DEBUG_ENDPOINTS=true일 때만 노출
+	if service.DebugEndpointsEnabled() {
+		mux.Handle("/traces", telemetry.TracesHandler())
+		handle(mux, "/resource", "resource", service.ResourceHandler)
+		handle(mux, "/admin/shutdown", "admin-shutdown", service.AdminShutdownHandler)
 	}
+	handle(mux, "/cascade", "cascade", cascadeHandler(cfg.ReceiverEndpoint))
+	handle(mux, "/chain", "chain", chainHandler(cfg.ReceiverEndpoint))
+	handle(mux, "/aws/instances", "aws-instances", awsInstancesHandler)
+	handle(mux, "/timeout-test", "timeout-test", timeoutTestHandler(cfg.ReceiverEndpoint))
+	handle(mux, "/propagation-check", "propagation-check", propagationCheckHandler(cfg.ReceiverEndpoint))
+	return mux
+}
 
-	client := otlptracegrpc.NewClient(
-		otlptracegrpc.WithEndpoint(tempoEndpoint),
-		otlptracegrpc.WithInsecure(), // 테스트 환경에서는 TLS 없이 설정
-	)
-	exporter, err := otlptrace.New(ctx, client)
-	if err != nil {
-		return nil, fmt.Errorf("OTLP exporter 생성 실패: %w", err)
-	}
+// 공통 미들웨어를 목록의 위쪽부터 바깥에 오도록 적용하고 OpenTelemetry로 감싸 핸들러를 등록
+func handle(mux *service.InstrumentedMux, pattern, operation string, h http.HandlerFunc) {
+	mux.HandleTraced(pattern, operation, service.Chain(h,
+		stats.Middleware(pattern),
+		service.WithMetrics(pattern),
+		service.WithRoute(pattern),
+		service.WithTraceIDHeader,
+		service.WithRequestID,
+		telemetry.WithBaggageTrimmed,
+		service.WithRequestAttributes,
+		service.WithStatusClass,
+		service.WithRecovery,
+		withTraceSource,
+		withTraceLabelsMiddleware,
+	))
+}
 
-	// 리소스 설정 (서비스 이름 등)
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String("monitoring-test-sender"), // 서비스 이름 변경
-			attribute.String("environment", "dev"),
-		),
-	)
+// DUMMY_JITTER(기본값 true)가 켜져 있으면 첫 더미 요청 전에 무작위로 기다린다
+func dummyJitterEnabled() bool {
+	v := os.Getenv("DUMMY_JITTER")
+	if v == "" {
+		return true
+	}
+	enabled, err := strconv.ParseBool(v)
 	if err != nil {
-		return nil, fmt.Errorf("리소스 생성 실패: %w", err)
+		log.Printf("잘못된 DUMMY_JITTER 값 %q, 기본값 true를 사용합니다.", v)
+		return true
 	}
+	return enabled
+}
 
-	// TracerProvider 설정
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(res),
-	)
-	otel.SetTracerProvider(tp)
-
-	// 글로벌 tracer 설정
-	tracer = tp.Tracer("monitoring-test-sender") // tracer 이름 변경
-
-	return tp, nil
+// 0~max 사이의 무작위 지터 (테스트에서 고정값으로 바꿀 수 있도록 변수로 둔다)
+var randomJitter = func(max time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(max)))
 }
 
 // 주기적인 더미 요청 생성을 위한 함수 추가
-func startPeriodicRequests(interval time.Duration) {
-	ticker := time.NewTicker(interval)
+// ctx가 취소되면 ticker를 멈추고 큐에 남은 작업을 마친 뒤 반환된 채널을 닫는다
+func startPeriodicRequests(ctx context.Context, cfg config.Config) <-chan struct{} {
+	interval := cfg.DummyInterval
+	// 요청이 밀려도 메모리가 무한히 늘지 않도록 워커 풀에서 처리
+	pool := newGeneratorPool(cfg.ServiceName)
+	workersDone := pool.start()
+
+	// 여러 인스턴스가 같은 시각에 요청하지 않도록 첫 틱 전에 0~interval 사이에서 무작위로 기다린다
+	var jitter time.Duration
+	if dummyJitterEnabled() {
+		jitter = randomJitter(interval)
+		log.Printf("첫 더미 요청 전 %v 대기합니다 (DUMMY_JITTER)", jitter)
+	}
+
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
+		stop := func() {
+			log.Println("주기적인 더미 요청 생성기를 중지합니다.")
+			pool.stop()
+			<-workersDone
+		}
+
+		select {
+		case <-time.After(jitter):
+		case <-ctx.Done():
+			stop()
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
-				generateDummyTraces()
+				pool.submit(func() { generateDummyTraces(cfg.ReceiverEndpoint) })
+			case <-ctx.Done():
+				stop()
+				return
 			}
 		}
 	}()
 	log.Printf("주기적인 더미 요청 생성기가 시작되었습니다 (간격: %v)", interval)
+	return done
 }
 
-// 다양한 엔드포인트에 더미 요청을 보내는 함수
-func generateDummyTraces() {
-	ctx := context.Background()
-	_, span := tracer.Start(ctx, "periodic-dummy-request")
-	defer span.End()
+// 더미 요청 하나에 허용하는 시간 (main에서 초기화)
+var dummyTimeout time.Duration
 
-	// receiver 주소 가져오기
-	receiverEndpoint := os.Getenv("RECEIVER_ENDPOINT")
-	if receiverEndpoint == "" {
-		receiverEndpoint = "http://localhost:8081" // 기본값
-		log.Println("RECEIVER_ENDPOINT 환경 변수가 설정되지 않았습니다. 기본값 http://localhost:8081을 사용합니다.")
-	}
+// 더미 요청 타임아웃을 환경 변수에서 가져오는 함수 (없거나 잘못된 값이면 3초)
+// receiver가 응답하지 않아도 워커가 무한정 묶이지 않도록 재시도를 포함한 전체 요청에 적용한다
+func getDummyRequestTimeout() time.Duration {
+	return config.PositiveDuration("DUMMY_REQUEST_TIMEOUT", 3*time.Second)
+}
+
+// 마지막으로 시작한 더미 요청 span (다음 요청이 링크를 건다)
+var (
+	lastDummySpanMu sync.Mutex
+	lastDummySpan   trace.SpanContext
+)
+
+// 더미 요청 span의 종류 (main에서 초기화)
+var dummySpanKind = trace.SpanKindClient
 
-	endpoints := []string{"/", "/health"} // receiver의 엔드포인트만 사용
+// DUMMY_SPAN_KIND(client, internal, producer, 기본값 client)로 더미 요청 span의 종류를 정한다
+// 더미 요청은 receiver를 호출하는 쪽이므로 기본값은 client이며, Tempo의 서비스 그래프도 이를 기준으로 그려진다
+func getDummySpanKind() trace.SpanKind {
+	kinds := map[string]trace.SpanKind{
+		"client":   trace.SpanKindClient,
+		"internal": trace.SpanKindInternal,
+		"producer": trace.SpanKindProducer,
+	}
+	v := os.Getenv("DUMMY_SPAN_KIND")
+	if v == "" {
+		return trace.SpanKindClient
+	}
+	kind, ok := kinds[v]
+	if !ok {
+		log.Printf("잘못된 DUMMY_SPAN_KIND 값 %q, 기본값 client를 사용합니다.", v)
+		return trace.SpanKindClient
+	}
+	return kind
+}
 
-	// 무작위 엔드포인트 선택
-	endpoint := endpoints[rand.Intn(len(endpoints))]
+// 더미 요청 span을 시작하면서 직전 더미 요청 span에 링크를 건다
+// 주기적인 요청들이 Tempo에서 사슬처럼 이어져 보인다
+func startLinkedDummySpan(ctx context.Context) (context.Context, trace.Span) {
+	lastDummySpanMu.Lock()
+	defer lastDummySpanMu.Unlock()
 
-	// 내부적으로 HTTP 요청 생성
-	client := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	opts := []trace.SpanStartOption{trace.WithSpanKind(dummySpanKind)}
+	if lastDummySpan.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{
+			SpanContext: lastDummySpan,
+			Attributes:  []attribute.KeyValue{attribute.String("link.type", "previous-dummy-request")},
+		}))
 	}
+	ctx, span := tracer.Start(ctx, "periodic-dummy-request", opts...)
+	lastDummySpan = span.SpanContext()
+	return ctx, span
+}
+
+// 더미 요청에 쓰는 클라이언트와 설정을 환경 변수로 초기화한다 (main과 테스트가 함께 사용)
+func initDummyRequests() {
+	// 연결을 재사용하는 공용 클라이언트 (더미 요청용은 서킷 브레이커 적용)
+	initHTTPClients()
+
+	dummyEndpoints = newEndpointPicker()
+	dummyRetry = getRetryPolicy()
+	dummyTimeout = getDummyRequestTimeout()
+	healthWindow = getHealthWindow()
+	dummySpanKind = getDummySpanKind()
+}
+
+// receiverEndpoint의 다양한 엔드포인트에 더미 요청을 보내는 함수
+func generateDummyTraces(receiverEndpoint string) {
+	// 생성기가 시작한 trace임을 baggage로 표시해 하위 서비스까지 전파
+	ctx := withGeneratorSource(context.Background())
+	// FEATURE_FLAGS로 지정한 기능 플래그와 TRACE_LABELS 레이블도 baggage로 함께 전파
+	ctx = withTraceLabels(withFeatureFlags(ctx))
+	// 요청 출발지도 baggage로 전파
+	ctx = withRequestOrigin(ctx)
+	ctx, span := startLinkedDummySpan(ctx)
+	defer span.End()
+	span.SetAttributes(attribute.String(traceSourceKey, traceSourceGenerator))
+
+	// 무작위 엔드포인트 선택 (DUMMY_ENDPOINT_WEIGHTS로 가중치 지정 가능)
+	endpoint := dummyEndpoints.pick()
+
+	// GRPC_ECHO_ENDPOINT가 설정되었으면 같은 trace 안에서 gRPC Echo도 호출
+	// HTTP 요청과 같은 DUMMY_REQUEST_TIMEOUT을 적용해 응답 없는 서버에 생성기가 묶이지 않게 한다
+	rpcCtx, rpcCancel := context.WithTimeout(ctx, dummyTimeout)
+	callEchoRPC(rpcCtx, endpoint)
+	rpcCancel()
+
+	// 응답이 늦어지면 DUMMY_REQUEST_TIMEOUT 후에 요청을 취소
+	reqCtx, cancel := context.WithTimeout(ctx, dummyTimeout)
+	defer cancel()
 
 	reqURL := fmt.Sprintf("%s%s", receiverEndpoint, endpoint) // receiver 주소 사용
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(reqCtx, "GET", reqURL, nil)
 	if err != nil {
-		log.Printf("더미 요청 생성 실패: %v", err)
+		slog.Error("더미 요청 생성 실패", "error", err)
+		recordDummyFailure(ctx, "build")
 		return
 	}
 
+	// 요청 ID를 만들어 보내면 receiver span에서도 같은 값으로 찾을 수 있다
+	requestID := uuid.NewString()
+	req.Header.Set(service.RequestIDHeader, requestID)
+
 	span.SetAttributes(attribute.String("dummy.request.url", reqURL))
+	span.SetAttributes(attribute.String("request.id", requestID))
 	span.SetAttributes(attribute.String("dummy.request.type", "periodic"))
 
-	resp, err := client.Do(req)
+	// 요청을 보낸 시점과 응답을 받은 시점을 이벤트로 남겨 Tempo에서 타임라인으로 볼 수 있게 한다
+	span.AddEvent("request.sent")
+
+	// 연결 오류는 지수 백오프로 재시도 (DUMMY_RETRY_MAX_ATTEMPTS, DUMMY_RETRY_BACKOFF)
+	resp, err := doWithRetry(dummyClient, req, dummyRetry)
 	if err != nil {
-		log.Printf("더미 요청 실패: %v", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			span.AddEvent("timeout", trace.WithAttributes(
+				attribute.Int64("timeout_ms", dummyTimeout.Milliseconds()),
+			))
+			span.SetStatus(codes.Error, "더미 요청 시간 초과")
+		}
+		slog.Error("더미 요청 실패", "error", err)
+		recordDummyFailure(ctx, "send")
 		return
 	}
 	defer resp.Body.Close()
+	span.AddEvent("response.received", trace.WithAttributes(attribute.Int("status", resp.StatusCode)))
+	if resp.StatusCode < http.StatusInternalServerError {
+		markDummySuccess()
+	}
+	// 본문을 끝까지 읽어야 연결이 풀로 돌아가 재사용된다
+	io.Copy(io.Discard, resp.Body)
 
-	log.Printf("더미 요청 완료: %s, 상태: %d", endpoint, resp.StatusCode)
+	service.LogWithTrace(ctx, "더미 요청 완료", "endpoint", endpoint, "status", resp.StatusCode)
 }
 
 func main() {
+	// 명령행 플래그 파싱 (지정한 플래그가 환경 변수보다 우선)
+	// 공통 설정 읽기 (OTEL_SERVICE_NAME, PORT, TEMPO_ENDPOINT, 샘플링 비율, RECEIVER_ENDPOINT, DUMMY_REQUEST_INTERVAL)
+	// 우선순위는 명령행 플래그 > 환경 변수 > 기본값
+	// 잘못된 값이 있으면 기본값으로 대신하지 않고 시작을 멈춘다
+	cfg, args, err := config.Load(config.Config{
+		ServiceName:      "monitoring-test-sender",
+		Port:             8080,
+		SampleRatio:      1.0,
+		ReceiverEndpoint: "http://localhost:8081",
+		DummyInterval:    5 * time.Second,
+	}, os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return // -h: 사용법만 출력하고 끝낸다
+	}
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	serviceName := cfg.ServiceName
+
+	// OTLP 로그 전송 준비 (OTEL_LOGS_ENDPOINT가 있을 때만)
+	lp, err := telemetry.InitLogger(context.Background(), serviceName)
+	if err != nil {
+		log.Fatalf("로거 provider 초기화 실패: %v", err)
+	}
+	if lp != nil {
+		defer telemetry.Shutdown("logger provider", lp.Shutdown)
+	}
+
+	// 로거 초기화 (LOG_SAMPLE_N, OTLP 로그 전송 여부)
+	service.InitLogger(serviceName, lp != nil)
+
+	// SDK 내부 오류 처리기 등록
+	service.InitErrorHandler(serviceName)
+
 	// 트레이서 초기화
-	tp, err := initTracer()
+	tp, err := telemetry.InitTracer(context.Background(), cfg)
 	if err != nil {
 		log.Fatalf("트레이서 초기화 실패: %v", err)
 	}
-	defer func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
-			log.Printf("Error shutting down tracer provider: %v", err)
+	tracer = tp.Tracer(serviceName)
+	defer telemetry.Shutdown("tracer provider", tp.Shutdown)
+
+	// 미터 초기화
+	mp, err := telemetry.InitMeter(context.Background(), serviceName)
+	if err != nil {
+		log.Fatalf("미터 초기화 실패: %v", err)
+	}
+	defer telemetry.Shutdown("meter provider", mp.Shutdown)
+	service.InitRequestMetrics(serviceName)
+	initDummyMetrics(serviceName)
+
+	// 더미 요청용 클라이언트와 설정 준비
+	initDummyRequests()
+	if err := initEchoClient(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if echoConn != nil {
+		defer echoConn.Close()
+	}
+
+	// loadtest 하위 명령이면 부하 테스트만 실행하고 종료
+	if len(args) > 0 && args[0] == "loadtest" {
+		if err := runLoadTest(args[1:], cfg.ReceiverEndpoint); err != nil {
+			slog.Error("부하 테스트 실패", "error", err)
 		}
-	}()
+		return
+	}
 
-	// 주기적인 더미 요청 시작 (5초마다)
-	startPeriodicRequests(5 * time.Second)
+	// 주기적인 더미 요청 시작 (DUMMY_REQUEST_INTERVAL, 기본값 5초)
+	// 서버가 종료되면 생성기를 멈추고, 진행 중인 요청의 span이 끝난 뒤 tracer provider가 종료되도록 기다린다
+	markDummySuccess() // 첫 요청 전까지는 시작 시각을 기준으로 삼는다
+	genCtx, stopGenerator := context.WithCancel(context.Background())
+	generatorDone := startPeriodicRequests(genCtx, cfg)
+	defer func() {
+		stopGenerator()
+		<-generatorDone
+	}()
 
-	// 서버 시작 X (sender는 더 이상 HTTP 서버가 아님)
 	log.Println("sender 시작됨. receiver로 요청 전송.")
 
-	// 대기
-	for {
+	// 진단용 핸들러 등록
+	mux := newMux(cfg)
+
+	// 진단 서버 시작
+	port := cfg.Port
+	log.Printf("sender 진단 서버가 포트 %d에서 시작됩니다...", port)
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+	if err := service.RunServer(srv); err != nil {
+		log.Fatalf("sender 진단 서버 시작 실패: %v", err)
 	}
 }
 
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+	"observability-playground/internal/telemetry"
+)
+
+// main과 같은 InitTracer로 전역 tracer를 설정하고, 끝난 span을 메모리 exporter에 모은다
+// 외부로는 아무것도 내보내지 않으며(OTEL_TRACES_EXPORTER=none), 테스트가 끝나면 provider를 정리한다
+func newTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	t.Setenv("TRACE_RECORDER_SIZE", "0")
+
+	cfg := config.Config{ServiceName: "monitoring-test-sender", SampleRatio: 1}
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := telemetry.InitTracer(context.Background(), cfg,
+		telemetry.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)),
+	)
+	if err != nil {
+		t.Fatalf("InitTracer: %v", err)
+	}
+	tracer = tp.Tracer(cfg.ServiceName)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+	return exporter
+}
+
+// 이름이 name인 span (없으면 테스트 실패)
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("%q span이 없습니다 (기록된 span %d개)", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func TestDummyRequestPropagatesTraceContext(t *testing.T) {
+	t.Setenv("DUMMY_ENDPOINTS", "/")
+	exporter := newTestTracer(t)
+	initDummyRequests()
+
+	// receiver 대신 otelhttp로 감싼 서버를 띄워 받은 traceparent와 서버 안의 span을 기록한다
+	var traceparent string
+	receiver := httptest.NewServer(otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		_, span := otel.Tracer("stub-receiver").Start(r.Context(), "stub-handler")
+		span.End()
+	}), "stub-receiver"))
+	defer receiver.Close()
+
+	generateDummyTraces(receiver.URL)
+
+	if traceparent == "" {
+		t.Fatal("receiver가 traceparent 헤더를 받지 못했습니다")
+	}
+
+	spans := exporter.GetSpans()
+	sender := findSpan(t, spans, "periodic-dummy-request")
+	handler := findSpan(t, spans, "stub-handler")
+	if handler.SpanContext.TraceID() != sender.SpanContext.TraceID() {
+		t.Fatalf("receiver span trace ID = %s, want sender와 같은 %s",
+			handler.SpanContext.TraceID(), sender.SpanContext.TraceID())
+	}
+
+	// receiver span에서 부모를 따라 올라가면 sender의 더미 요청 span에 닿아야 한다
+	parents := map[trace.SpanID]trace.SpanID{}
+	for _, s := range spans {
+		parents[s.SpanContext.SpanID()] = s.Parent.SpanID()
+	}
+	id := handler.Parent.SpanID()
+	for id.IsValid() && id != sender.SpanContext.SpanID() {
+		id = parents[id]
+	}
+	if id != sender.SpanContext.SpanID() {
+		t.Errorf("stub-handler span이 periodic-dummy-request span의 자손이 아닙니다")
+	}
+}
+
+func TestStartPeriodicRequestsStopsOnCancel(t *testing.T) {
+	for _, jitter := range []string{"true", "false"} {
+		t.Run("DUMMY_JITTER="+jitter, func(t *testing.T) {
+			t.Setenv("DUMMY_JITTER", jitter)
+			// 간격을 길게 잡아 테스트 중에는 요청을 보내지 않는다
+			cfg := config.Config{ReceiverEndpoint: "http://127.0.0.1:0", DummyInterval: time.Hour}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := startPeriodicRequests(ctx, cfg)
+			time.Sleep(10 * time.Millisecond) // 지터 대기나 ticker 루프에 들어갈 때까지
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("context를 취소한 뒤에도 생성기 goroutine이 끝나지 않았습니다")
+			}
+		})
+	}
+}
+
+func TestDummyRequestTimesOut(t *testing.T) {
+	t.Setenv("DUMMY_ENDPOINTS", "/slow")
+	t.Setenv("DUMMY_REQUEST_TIMEOUT", "50ms")
+	exporter := newTestTracer(t)
+	initDummyRequests()
+
+	// 제한 시간보다 오래 응답하지 않다가, 클라이언트가 요청을 취소하면 알린다
+	cancelled := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer receiver.Close()
+
+	start := time.Now()
+	generateDummyTraces(receiver.URL)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("generateDummyTraces가 %v 동안 기다렸습니다 (제한 시간 50ms)", elapsed)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("제한 시간이 지났는데 receiver 쪽 요청이 취소되지 않았습니다")
+	}
+
+	span := findSpan(t, exporter.GetSpans(), "periodic-dummy-request")
+	if span.Status.Code != codes.Error {
+		t.Errorf("span 상태 = %v, want Error", span.Status.Code)
+	}
+	var timeoutEvent bool
+	for _, e := range span.Events {
+		if e.Name == "timeout" {
+			timeoutEvent = true
+		}
+	}
+	if !timeoutEvent {
+		t.Error("span에 timeout 이벤트가 없습니다")
+	}
+}
+
+func TestDummySpanLinksToPreviousOne(t *testing.T) {
+	exporter := newTestTracer(t)
+	lastDummySpan = trace.SpanContext{} // 앞선 테스트의 더미 요청과 이어지지 않게 한다
+
+	for i := 0; i < 2; i++ {
+		_, span := startLinkedDummySpan(context.Background())
+		span.End()
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("span 수 = %d, want 2", len(spans))
+	}
+	first, second := spans[0], spans[1]
+	if len(first.Links) != 0 {
+		t.Errorf("첫 더미 요청 span의 링크 = %d개, want 0", len(first.Links))
+	}
+	if len(second.Links) != 1 || second.Links[0].SpanContext.SpanID() != first.SpanContext.SpanID() {
+		t.Errorf("두 번째 더미 요청 span의 링크 = %+v, want 첫 span %s", second.Links, first.SpanContext.SpanID())
+	}
+}
+
+func TestDummySpanMarksSendAndResponseEvents(t *testing.T) {
+	t.Setenv("DUMMY_ENDPOINTS", "/")
+	exporter := newTestTracer(t)
+	initDummyRequests()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	generateDummyTraces(receiver.URL)
+
+	span := findSpan(t, exporter.GetSpans(), "periodic-dummy-request")
+	var names []string
+	for _, e := range span.Events {
+		names = append(names, e.Name)
+		if e.Name != "response.received" {
+			continue
+		}
+		var status any
+		for _, kv := range e.Attributes {
+			if kv.Key == "status" {
+				status = kv.Value.AsInterface()
+			}
+		}
+		if status != int64(http.StatusAccepted) {
+			t.Errorf("response.received status = %v, want %d", status, http.StatusAccepted)
+		}
+	}
+	if !slices.Equal(names, []string{"request.sent", "response.received"}) {
+		t.Errorf("더미 요청 span 이벤트 = %q, want request.sent, response.received", names)
+	}
+}
+
+func TestDummySpanKind(t *testing.T) {
+	tests := []struct {
+		value string
+		want  trace.SpanKind
+	}{
+		{"", trace.SpanKindClient},
+		{"internal", trace.SpanKindInternal},
+		{"producer", trace.SpanKindProducer},
+		{"server", trace.SpanKindClient}, // 잘못된 값은 기본값
+	}
+	for _, tt := range tests {
+		t.Run("DUMMY_SPAN_KIND="+tt.value, func(t *testing.T) {
+			t.Setenv("DUMMY_SPAN_KIND", tt.value)
+			exporter := newTestTracer(t)
+			initDummyRequests()
+
+			_, span := startLinkedDummySpan(context.Background())
+			span.End()
+			if got := findSpan(t, exporter.GetSpans(), "periodic-dummy-request").SpanKind; got != tt.want {
+				t.Errorf("더미 요청 span 종류 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstPeriodicRequestWaitsForJitter(t *testing.T) {
+	t.Setenv("DUMMY_JITTER", "true")
+	t.Setenv("DUMMY_ENDPOINTS", "/")
+	newTestTracer(t)
+	initDummyRequests()
+	const jitter = 200 * time.Millisecond
+	prev := randomJitter
+	randomJitter = func(time.Duration) time.Duration { return jitter }
+	t.Cleanup(func() { randomJitter = prev })
+
+	first := make(chan time.Time, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case first <- time.Now():
+		default:
+		}
+	}))
+	defer receiver.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	done := startPeriodicRequests(ctx, config.Config{
+		ServiceName:      "monitoring-test-sender",
+		ReceiverEndpoint: receiver.URL,
+		DummyInterval:    20 * time.Millisecond,
+	})
+	defer func() { cancel(); <-done }()
+
+	select {
+	case at := <-first:
+		if elapsed := at.Sub(start); elapsed < jitter {
+			t.Errorf("첫 더미 요청이 %v 만에 도착했습니다, want 지터 %v 이후", elapsed, jitter)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("첫 더미 요청이 도착하지 않았습니다")
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// 더미 요청 실패 수 (reason: build, send)
+var dummyFailureCounter metric.Int64Counter
+
+// 전역 MeterProvider의 serviceName 이름 meter에 더미 요청 메트릭 instrument 생성
+func initDummyMetrics(serviceName string) {
+	var err error
+	dummyFailureCounter, err = otel.Meter(serviceName).Int64Counter("dummy_request_failures_total",
+		metric.WithDescription("실패한 더미 요청 수"),
+	)
+	if err != nil {
+		log.Printf("더미 요청 실패 카운터 생성 실패: %v", err)
+	}
+}
+
+// 더미 요청 실패를 reason 속성과 함께 집계
+func recordDummyFailure(ctx context.Context, reason string) {
+	if dummyFailureCounter != nil {
+		dummyFailureCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+	}
+}
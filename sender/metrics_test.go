@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// reader에 모인 dummy_request_failures_total 값 (reason 속성별)
+func dummyFailures(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "dummy_request_failures_total" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				reason, _ := dp.Attributes.Value(attribute.Key("reason"))
+				got[reason.AsString()] += dp.Value
+			}
+		}
+	}
+	return got
+}
+
+func TestSendFailureIncrementsFailureCounter(t *testing.T) {
+	t.Setenv("DUMMY_ENDPOINTS", "/")
+	t.Setenv("DUMMY_RETRY_MAX_ATTEMPTS", "1")
+	newTestTracer(t)
+	initDummyRequests()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	prev := otel.GetMeterProvider()
+	otel.SetMeterProvider(mp)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(prev)
+		mp.Shutdown(context.Background())
+	})
+	initDummyMetrics("monitoring-test-sender")
+
+	// 닫힌 서버로 보내 연결 오류를 만든다
+	receiver := httptest.NewServer(nil)
+	receiver.Close()
+	generateDummyTraces(receiver.URL)
+
+	if got := dummyFailures(t, reader); got["send"] != 1 || got["build"] != 0 {
+		t.Errorf("dummy_request_failures_total = %v, want send 1", got)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/service"
+)
+
+// 실제로 전송된 traceparent 헤더를 기록하는 RoundTripper
+// otelhttp Transport 안쪽에 두어 주입이 끝난 요청을 볼 수 있게 한다
+// 공용 클라이언트에 들어 있으므로, withTraceparentCapture로 기록할 곳을 넣은 요청만 기록한다
+type traceparentCapture struct {
+	base http.RoundTripper
+}
+
+type traceparentKey struct{}
+
+// ctx로 보내는 요청의 traceparent 헤더를 *dst에 기록하게 한다
+func withTraceparentCapture(ctx context.Context, dst *string) context.Context {
+	return context.WithValue(ctx, traceparentKey{}, dst)
+}
+
+func (c traceparentCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	if dst, ok := req.Context().Value(traceparentKey{}).(*string); ok {
+		*dst = req.Header.Get("traceparent")
+	}
+	return c.base.RoundTrip(req)
+}
+
+// receiver의 /echo 응답 형식
+type echoResponse struct {
+	Traceparent string `json:"traceparent"`
+}
+
+// 전파 점검 결과
+type propagationCheckResult struct {
+	Pass                bool   `json:"pass"`
+	SentTraceparent     string `json:"sent_traceparent"`
+	ReceivedTraceparent string `json:"received_traceparent"`
+	Error               string `json:"error,omitempty"`
+}
+
+// /echo 호출 제한 시간 (테스트에서 줄일 수 있도록 변수로 둔다)
+var propagationCheckTimeout = 5 * time.Second
+
+// receiver의 /echo를 호출해 trace context가 제대로 전파되는지 확인하는 핸들러
+func propagationCheckHandler(receiverEndpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "propagation-check")
+		defer span.End()
+
+		result := propagationCheckResult{}
+		// receiver가 응답하지 않아도 점검이 끝나도록 제한 시간을 건다
+		reqCtx, cancel := context.WithTimeout(withTraceparentCapture(ctx, &result.SentTraceparent), propagationCheckTimeout)
+		defer cancel()
+
+		reqURL := fmt.Sprintf("%s/echo", receiverEndpoint)
+		req, err := http.NewRequestWithContext(reqCtx, "GET", reqURL, nil)
+		if err == nil {
+			var resp *http.Response
+			resp, err = downstreamClient.Do(req)
+			if err == nil {
+				defer resp.Body.Close()
+
+				var echo echoResponse
+				err = json.NewDecoder(resp.Body).Decode(&echo)
+				result.ReceivedTraceparent = echo.Traceparent
+			}
+		}
+
+		if err != nil {
+			slog.Error("전파 점검 요청 실패", "error", err)
+			result.Error = err.Error()
+			span.RecordError(err)
+		}
+		result.Pass = err == nil && result.SentTraceparent != "" &&
+			result.SentTraceparent == result.ReceivedTraceparent
+
+		span.SetAttributes(
+			attribute.Bool("propagation.check.pass", result.Pass),
+			attribute.String("propagation.check.sent", result.SentTraceparent),
+			attribute.String("propagation.check.received", result.ReceivedTraceparent),
+		)
+		if !result.Pass {
+			span.SetStatus(codes.Error, "trace context 전파 실패")
+		}
+
+		service.LogWithTrace(r.Context(), "전파 점검 완료", "pass", result.Pass, "sent", result.SentTraceparent, "received", result.ReceivedTraceparent)
+
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Pass {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(result)
+	}
+}
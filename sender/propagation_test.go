@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// /propagation-check를 호출하고 결과 JSON을 반환
+func runPropagationCheck(t *testing.T, receiverURL string) (int, propagationCheckResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	propagationCheckHandler(receiverURL)(rec, httptest.NewRequest(http.MethodGet, "/propagation-check", nil))
+	var result propagationCheckResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("응답 JSON 해석 실패: %v", err)
+	}
+	return rec.Code, result
+}
+
+func TestPropagationCheckComparesEchoedTraceparent(t *testing.T) {
+	newTestTracer(t)
+	initHTTPClients()
+
+	// receiver의 /echo처럼 받은 traceparent를 그대로 돌려준다
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(echoResponse{Traceparent: r.Header.Get("traceparent")})
+	}))
+	t.Cleanup(receiver.Close)
+
+	code, result := runPropagationCheck(t, receiver.URL)
+	if code != http.StatusOK || !result.Pass || result.SentTraceparent == "" {
+		t.Errorf("점검 결과 = %d %+v, want 200과 pass", code, result)
+	}
+}
+
+func TestPropagationCheckGivesUpAtDeadline(t *testing.T) {
+	newTestTracer(t)
+	initHTTPClients()
+	prev := propagationCheckTimeout
+	propagationCheckTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { propagationCheckTimeout = prev })
+
+	// 응답하지 않는 receiver
+	release := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(receiver.Close)
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	code, result := runPropagationCheck(t, receiver.URL)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("점검에 %v 걸렸습니다, want 제한 시간 근처에서 종료", elapsed)
+	}
+	if code != http.StatusServiceUnavailable || result.Pass || result.Error == "" {
+		t.Errorf("점검 결과 = %d %+v, want 503과 오류", code, result)
+	}
+}
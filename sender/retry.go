@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"observability-playground/internal/config"
+)
+
+// 더미 요청 재시도 설정
+// DUMMY_RETRY_MAX_ATTEMPTS: 첫 시도를 포함한 최대 시도 횟수 (기본값 3, 1이면 재시도하지 않음)
+// DUMMY_RETRY_BACKOFF: 첫 재시도 전 대기 시간, 이후 두 배씩 늘어난다 (기본값 200ms)
+type retryPolicy struct {
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func getRetryPolicy() retryPolicy {
+	return retryPolicy{
+		maxAttempts: config.PositiveInt("DUMMY_RETRY_MAX_ATTEMPTS", 3),
+		backoff:     config.PositiveDuration("DUMMY_RETRY_BACKOFF", 200*time.Millisecond),
+	}
+}
+
+// 더미 요청 재시도 정책 (main에서 초기화)
+var dummyRetry retryPolicy
+
+// 요청을 보내고, 연결 오류가 나면 지수 백오프로 재시도
+// 재시도할 때마다 span에 retry 이벤트를 남겨 Tempo에서 재시도 과정을 볼 수 있다
+// 서킷이 열려 있거나 요청 context가 끝났으면 재시도해도 소용없으므로 바로 포기한다
+func doWithRetry(client *http.Client, req *http.Request, policy retryPolicy) (*http.Response, error) {
+	span := trace.SpanFromContext(req.Context())
+	backoff := policy.backoff
+
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if err == nil || attempt >= policy.maxAttempts || errors.Is(err, errCircuitOpen) || req.Context().Err() != nil {
+			span.SetAttributes(attribute.Int("retry.attempts", attempt))
+			return resp, err
+		}
+
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt),
+			attribute.Int64("retry.backoff_ms", backoff.Milliseconds()),
+			attribute.String("error", err.Error()),
+		))
+		slog.WarnContext(req.Context(), "더미 요청 실패, 재시도합니다", "attempt", attempt, "backoff", backoff, "error", err)
+
+		// 대기 중에 요청 context가 끝나면 남은 백오프를 기다리지 않고 바로 포기
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			span.SetAttributes(attribute.Int("retry.attempts", attempt))
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
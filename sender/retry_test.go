@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 응답 없이 연결을 끊어 클라이언트에 연결 오류를 내는 서버
+func newDroppingServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		conn.Close()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDoWithRetryRetriesConnectionErrors(t *testing.T) {
+	exporter := newTestTracer(t)
+	var hits atomic.Int32
+	srv := newDroppingServer(t, &hits)
+
+	ctx, span := tracer.Start(context.Background(), "retry-test")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	policy := retryPolicy{maxAttempts: 3, backoff: time.Millisecond}
+
+	if _, err := doWithRetry(client, req, policy); err == nil {
+		t.Fatal("연결이 끊겼는데 오류가 없습니다")
+	}
+	span.End()
+
+	if got := hits.Load(); got != 3 {
+		t.Errorf("서버가 받은 요청 = %d, want 3", got)
+	}
+	s := findSpan(t, exporter.GetSpans(), "retry-test")
+	var retries []int64
+	for _, e := range s.Events {
+		if e.Name != "retry" {
+			continue
+		}
+		for _, kv := range e.Attributes {
+			if kv.Key == "retry.attempt" {
+				retries = append(retries, kv.Value.AsInt64())
+			}
+		}
+	}
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("retry 이벤트의 retry.attempt = %v, want [1 2]", retries)
+	}
+	var attempts int64
+	for _, kv := range s.Attributes {
+		if kv.Key == "retry.attempts" {
+			attempts = kv.Value.AsInt64()
+		}
+	}
+	if attempts != 3 {
+		t.Errorf("retry.attempts = %d, want 3", attempts)
+	}
+}
+
+func TestDoWithRetryStopsWhenContextEnds(t *testing.T) {
+	newTestTracer(t)
+	var hits atomic.Int32
+	srv := newDroppingServer(t, &hits)
+
+	// 백오프가 제한 시간보다 길어 첫 실패 후 대기 중에 포기해야 한다
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	start := time.Now()
+	if _, err := doWithRetry(client, req, retryPolicy{maxAttempts: 5, backoff: time.Minute}); err == nil {
+		t.Fatal("오류가 없습니다")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("context가 끝난 뒤에도 %v 동안 기다렸습니다", elapsed)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("서버가 받은 요청 = %d, want 1", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"observability-playground/internal/service"
+)
+
+// 짧은 deadline을 건 채로 receiver의 /slow를 호출해 deadline 전파를 보여주는 핸들러
+// 제한 시간은 timeout 쿼리(없으면 TIMEOUT_TEST_DEADLINE, 기본값 500ms)로 지정한다
+// 클라이언트가 요청을 취소하면 receiver의 요청 context도 취소되어 양쪽 span에 기록된다
+func timeoutTestHandler(receiverEndpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deadline := 500 * time.Millisecond
+		for _, v := range []string{r.URL.Query().Get("timeout"), os.Getenv("TIMEOUT_TEST_DEADLINE")} {
+			if v == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(v); err == nil && d > 0 {
+				deadline = d
+				break
+			}
+			log.Printf("잘못된 제한 시간 값 %q, 무시합니다.", v)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), deadline)
+		defer cancel()
+
+		ctx, span := tracer.Start(ctx, "timeout-test")
+		defer span.End()
+		span.SetAttributes(attribute.Int64("timeout.deadline_ms", deadline.Milliseconds()))
+
+		service.LogWithTrace(ctx, "deadline 전파 테스트 시작", "deadline", deadline)
+
+		reqURL := fmt.Sprintf("%s/slow", receiverEndpoint)
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			slog.Error("deadline 테스트 요청 생성 실패", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "요청 생성 실패")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		start := time.Now()
+		resp, err := downstreamClient.Do(req)
+		elapsed := time.Since(start)
+		span.SetAttributes(attribute.Int64("timeout.elapsed_ms", elapsed.Milliseconds()))
+
+		if err != nil {
+			cancelled := errors.Is(err, context.DeadlineExceeded)
+			span.SetAttributes(
+				attribute.Bool("downstream.completed", false),
+				attribute.Bool("downstream.cancelled", cancelled),
+			)
+			span.RecordError(err)
+
+			if cancelled {
+				slog.Warn("deadline 초과로 하위 요청 취소됨", "elapsed", elapsed)
+				span.SetStatus(codes.Error, "deadline 초과로 하위 요청 취소")
+				w.WriteHeader(http.StatusGatewayTimeout)
+				fmt.Fprintf(w, "하위 요청이 deadline(%v)으로 취소되었습니다. 경과: %v\n", deadline, elapsed.Round(time.Millisecond))
+				return
+			}
+
+			slog.Error("deadline 테스트 요청 실패", "error", err)
+			span.SetStatus(codes.Error, "하위 요청 실패")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintf(w, "하위 요청 실패: %v\n", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		span.SetAttributes(
+			attribute.Bool("downstream.completed", true),
+			attribute.Bool("downstream.cancelled", false),
+		)
+		service.LogWithTrace(ctx, "하위 요청이 deadline 내에 완료됨", "elapsed", elapsed, "status", resp.StatusCode)
+		fmt.Fprintf(w, "하위 요청이 deadline(%v) 내에 완료되었습니다. 경과: %v\n", deadline, elapsed.Round(time.Millisecond))
+	}
+}
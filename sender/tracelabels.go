@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// TRACE_LABELS 환경 변수(예: "experiment=A,cohort=beta")를 baggage 항목으로 변환
+// receiver는 허용 목록에 있는 항목을 모든 span의 속성으로 올린다
+func parseTraceLabels(v string) []baggage.Member {
+	var members []baggage.Member
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Printf("잘못된 trace 레이블 %q (형식: key=value), 무시합니다.", pair)
+			continue
+		}
+		member, err := baggage.NewMember(strings.TrimSpace(key), strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("잘못된 trace 레이블 %q, 무시합니다: %v", pair, err)
+			continue
+		}
+		members = append(members, member)
+	}
+	return members
+}
+
+// 시작 시 한 번 읽어 둔 trace 레이블
+var traceLabels = parseTraceLabels(os.Getenv("TRACE_LABELS"))
+
+// trace 레이블을 baggage에 넣은 context 반환 (trace 전체에 전파됨)
+func withTraceLabels(ctx context.Context) context.Context {
+	return withBaggageMembers(ctx, traceLabels)
+}
+
+// sender가 처리하는 요청에서 나가는 하위 요청에도 trace 레이블을 싣는 미들웨어
+func withTraceLabelsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(withTraceLabels(r.Context())))
+	})
+}
+
+// 주어진 항목들을 baggage에 추가한 context 반환
+func withBaggageMembers(ctx context.Context, members []baggage.Member) context.Context {
+	if len(members) == 0 {
+		return ctx
+	}
+	bag := baggage.FromContext(ctx)
+	for _, member := range members {
+		var err error
+		if bag, err = bag.SetMember(member); err != nil {
+			log.Printf("baggage 설정 실패: %v", err)
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// trace가 어디서 시작되었는지 나타내는 속성/baggage 키
+//
+//   - 주기적인 더미 요청 생성기가 시작한 trace: 루트 span에 trace.source=generator를 기록하고
+//     같은 값을 baggage로 하위 서비스까지 전파한다
+//   - 그 밖의 요청(외부에서 들어온 요청): baggage에 표시가 없으므로 trace.source=external
+//
+// 대시보드에서는 trace.source로 합성 트래픽과 실제 트래픽을 구분할 수 있다
+const (
+	traceSourceKey       = "trace.source"
+	traceSourceGenerator = "generator"
+	traceSourceExternal  = "external"
+)
+
+// 생성기 표시를 baggage에 넣은 context 반환
+func withGeneratorSource(ctx context.Context) context.Context {
+	member, err := baggage.NewMember(traceSourceKey, traceSourceGenerator)
+	if err != nil {
+		log.Printf("baggage 항목 생성 실패: %v", err)
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		log.Printf("baggage 설정 실패: %v", err)
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// 요청을 보낸 서비스를 나타내는 baggage 키
+// receiver는 이 값을 span 속성으로 올려 여러 단계를 거친 요청의 출발지를 확인할 수 있게 한다
+const requestOriginKey = "request.origin"
+
+// 요청 출발지(request.origin=sender)를 baggage에 넣은 context 반환
+func withRequestOrigin(ctx context.Context) context.Context {
+	member, err := baggage.NewMember(requestOriginKey, "sender")
+	if err != nil {
+		log.Printf("baggage 항목 생성 실패: %v", err)
+		return ctx
+	}
+	return withBaggageMembers(ctx, []baggage.Member{member})
+}
+
+// 들어온 baggage를 보고 서버 span에 trace.source를 기록하는 미들웨어
+func withTraceSource(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		source := baggage.FromContext(r.Context()).Member(traceSourceKey).Value()
+		if source == "" {
+			source = traceSourceExternal
+		}
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String(traceSourceKey, source))
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"observability-playground/internal/config"
+)
+
+// 더미 요청 작업을 처리하는 고정 크기 워커 풀
+// 큐가 가득 차면 설정에 따라 작업을 버리거나(drop) 자리가 날 때까지 기다린다(block)
+type generatorPool struct {
+	jobs    chan func()
+	workers int
+	block   bool
+}
+
+// 환경 변수에서 워커 풀 설정을 읽어 생성
+// GENERATOR_WORKERS: 워커 수 (기본값 1)
+// GENERATOR_QUEUE_SIZE: 대기 큐 크기 (기본값 10)
+// GENERATOR_QUEUE_FULL: 큐가 가득 찼을 때 동작, drop 또는 block (기본값 drop)
+// 큐 깊이 메트릭은 serviceName 이름의 meter에 만든다
+func newGeneratorPool(serviceName string) *generatorPool {
+	workers := config.PositiveInt("GENERATOR_WORKERS", 1)
+	queueSize := config.PositiveInt("GENERATOR_QUEUE_SIZE", 10)
+
+	block := false
+	switch v := os.Getenv("GENERATOR_QUEUE_FULL"); v {
+	case "", "drop":
+	case "block":
+		block = true
+	default:
+		log.Printf("잘못된 GENERATOR_QUEUE_FULL 값 %q, 기본값 drop을 사용합니다.", v)
+	}
+
+	p := &generatorPool{
+		jobs:    make(chan func(), queueSize),
+		workers: workers,
+		block:   block,
+	}
+
+	// 큐 깊이를 메트릭으로 노출
+	meter := otel.Meter(serviceName)
+	_, err := meter.Int64ObservableGauge("generator.queue.depth",
+		metric.WithDescription("더미 요청 워커 풀의 대기 작업 수"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(len(p.jobs)))
+			return nil
+		}),
+	)
+	if err != nil {
+		log.Printf("큐 깊이 메트릭 등록 실패: %v", err)
+	}
+
+	log.Printf("워커 풀 설정: 워커 %d개, 큐 크기 %d, 큐 가득 참 시 block=%t", workers, queueSize, block)
+	return p
+}
+
+// 워커 고루틴 시작. 모든 워커가 끝나면 반환된 채널이 닫힌다
+func (p *generatorPool) start() <-chan struct{} {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// 더 이상 작업을 받지 않도록 큐를 닫는다. 남은 작업은 워커가 마저 처리한다
+// submit을 호출하는 고루틴에서만 호출해야 한다
+func (p *generatorPool) stop() {
+	close(p.jobs)
+}
+
+// 작업을 큐에 넣는다. 버려진 경우 false를 반환
+func (p *generatorPool) submit(job func()) bool {
+	if p.block {
+		p.jobs <- job
+		return true
+	}
+
+	select {
+	case p.jobs <- job:
+		return true
+	default:
+		log.Printf("워커 풀 큐가 가득 차 더미 요청을 버립니다 (큐 크기: %d)", cap(p.jobs))
+		return false
+	}
+}